@@ -0,0 +1,23 @@
+package rlog
+
+import "sync/atomic"
+
+// The atomicBox holds a value of 'T' which is loaded and stored atomically, the
+// zero value holds the zero 'T'. The value is boxed, since the 'atomic.Value'
+// requires the same concrete type, and rejects nil.
+type atomicBox[T any] struct {
+	v atomic.Value // box[T]
+}
+
+type box[T any] struct {
+	v T
+}
+
+func (b *atomicBox[T]) Load() T {
+	x, _ := b.v.Load().(box[T])
+	return x.v
+}
+
+func (b *atomicBox[T]) Store(v T) {
+	b.v.Store(box[T]{v: v})
+}
@@ -0,0 +1,110 @@
+package rlog
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+const (
+	// The key of the attribute created by 'Err()'.
+	KEY_ERROR = "error"
+
+	// The key of the cause of the error logged with 'KEY_ERROR', the cause of
+	// the error logged with other keys is suffixed, ex: "err_cause".
+	KEY_CAUSE = "cause"
+)
+
+// The constructors of the attributes, pass them to the log methods or 'With()'
+// directly without the keys, ex: 'logger.Info("hi", rlog.Int("n", 1))'.
+
+func Str(key, val string) LogAttr {
+	return LogAttr{Key: key, Value: val}
+}
+
+func Int(key string, val int) LogAttr {
+	return LogAttr{Key: key, Value: val}
+}
+
+func Bool(key string, val bool) LogAttr {
+	return LogAttr{Key: key, Value: val}
+}
+
+func Time(key string, val time.Time) LogAttr {
+	return LogAttr{Key: key, Value: val}
+}
+
+func Any(key string, val any) LogAttr {
+	return LogAttr{Key: key, Value: val}
+}
+
+// The JSON handler writes the duration in the string form or milliseconds, see
+// 'HandlerOptions.DurationFormat'.
+func Dur(key string, d time.Duration) LogAttr {
+	return LogAttr{Key: key, Value: d}
+}
+
+// Return an attribute of the bytes encoded in hex, ex: "0a1b".
+func Hex(key string, b []byte) LogAttr {
+	return LogAttr{Key: key, Value: hex.EncodeToString(b)}
+}
+
+// Return an attribute of the bytes encoded in the standard base64. Note that
+// the built-in handlers write the raw '[]byte' values in base64 too.
+func Base64(key string, b []byte) LogAttr {
+	return LogAttr{Key: key, Value: base64.StdEncoding.EncodeToString(b)}
+}
+
+// Return an attribute of the error with 'KEY_ERROR'.
+//
+// The built-in handlers write the 'err.Error()' of the error values, and the
+// one of 'errors.Unwrap(err)' as the cause if any.
+func Err(err error) LogAttr {
+	return LogAttr{Key: KEY_ERROR, Value: err}
+}
+
+// Expand the error to the attributes of its message and cause.
+func errorAttrs(key string, err error) []LogAttr {
+	attrs := []LogAttr{{Key: key, Value: err.Error()}}
+
+	if cause := errors.Unwrap(err); cause != nil {
+		causeKey := KEY_CAUSE
+		if key != KEY_ERROR {
+			causeKey = key + "_" + KEY_CAUSE
+		}
+
+		attrs = append(attrs, LogAttr{Key: causeKey, Value: cause.Error()})
+	}
+
+	return attrs
+}
+
+// Collapse the attributes with the same key, the last value wins and the
+// position of the first one is kept. The groups are collapsed recursively.
+func dedupAttrs(attrs []LogAttr) []LogAttr {
+	index := make(map[string]int, len(attrs))
+	deduped := make([]LogAttr, 0, len(attrs))
+
+	for _, attr := range attrs {
+		if group, ok := attr.Value.([]LogAttr); ok {
+			attr.Value = dedupAttrs(group)
+
+			// The inlined groups never collapse with each other.
+			if attr.Key == "" {
+				deduped = append(deduped, attr)
+				continue
+			}
+		}
+
+		if i, ok := index[attr.Key]; ok {
+			deduped[i] = attr
+			continue
+		}
+
+		index[attr.Key] = len(deduped)
+		deduped = append(deduped, attr)
+	}
+
+	return deduped
+}
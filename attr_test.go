@@ -0,0 +1,130 @@
+package rlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValuerIsLazy(t *testing.T) {
+	calls := 0
+	v := Valuer(func() any {
+		calls++
+		return "computed"
+	})
+
+	buf := bytes.Buffer{}
+	h := NewTextHandler(&buf, LogLevelWarn)
+	logger := newLogger(h)
+
+	logger.Info("dropped", "k", v)
+	if calls != 0 {
+		t.Errorf("got %d calls of the disabled record, want 0", calls)
+	}
+
+	logger.Warn("hi", "k", v)
+	if calls != 1 || !strings.HasSuffix(buf.String(), " WARN hi k=computed\n") {
+		t.Errorf("got %d calls and %q", calls, buf.String())
+	}
+
+	if a := (LogAttr{Key: "k", Value: v}).Resolve(); a.Value != "computed" {
+		t.Errorf("got resolved %v", a)
+	}
+}
+
+func TestGroupRendering(t *testing.T) {
+	attrs := []LogAttr{
+		Group("http", "method", "GET", Group("resp", "status", 200)),
+		Group("", "inlined", true),
+	}
+
+	got := writeJSON(nil, LogRecord{Level: LogLevelInfo, Message: "m", Attrs: attrs})
+	want := `{"level":"INFO","msg":"m","http":{"method":"GET","resp":{"status":200}},` +
+		`"inlined":true}` + "\n"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	buf := bytes.Buffer{}
+	NewTextHandler(&buf, LogLevelInfo).Handle(LogRecord{
+		Level:   LogLevelInfo,
+		Message: "m",
+		Attrs:   attrs,
+	})
+	if want := "INFO m http.method=GET http.resp.status=200 inlined=true\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestErrorAttrs(t *testing.T) {
+	cause := errors.New("refused")
+	err := fmt.Errorf("dial: %w", cause)
+
+	got := writeJSON(nil, LogRecord{
+		Level:   LogLevelError,
+		Message: "m",
+		Attrs:   []LogAttr{Err(err), Any("last", cause)},
+	})
+
+	want := `{"level":"ERROR","msg":"m","error":"dial: refused","cause":"refused",` +
+		`"last":"refused"}` + "\n"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	buf := bytes.Buffer{}
+	NewTextHandler(&buf, LogLevelInfo).Handle(LogRecord{
+		Level:   LogLevelError,
+		Message: "m",
+		Attrs:   []LogAttr{Any("err", err)},
+	})
+	if want := `ERROR m err="dial: refused" err_cause=refused` + "\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTypedAttrs(t *testing.T) {
+	at := time.Unix(0, 0)
+
+	for _, tc := range []struct {
+		attr LogAttr
+		want LogAttr
+	}{
+		{Str("s", "v"), LogAttr{Key: "s", Value: "v"}},
+		{Int("i", 1), LogAttr{Key: "i", Value: 1}},
+		{Bool("b", true), LogAttr{Key: "b", Value: true}},
+		{Time("t", at), LogAttr{Key: "t", Value: at}},
+		{Any("a", 1.5), LogAttr{Key: "a", Value: 1.5}},
+	} {
+		if tc.attr != tc.want {
+			t.Errorf("got %v, want %v", tc.attr, tc.want)
+		}
+	}
+
+	// Passed without the keys.
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	newLogger(mem).Info("m", Int("n", 1), "k", "v", []LogAttr{Bool("b", true)})
+
+	if rs := snapshot(); len(rs[0].Attrs) != 3 || rs[0].Attrs[2].Key != "b" {
+		t.Errorf("got %v", rs[0].Attrs)
+	}
+}
+
+func TestByteAttrs(t *testing.T) {
+	b := []byte{0x0a, 0x1b, 0xff}
+
+	if a := Hex("h", b); a.Value != "0a1bff" {
+		t.Errorf("got %v", a)
+	}
+	if a := Base64("b", b); a.Value != "Chv/" {
+		t.Errorf("got %v", a)
+	}
+
+	got := writeJSON(nil, LogRecord{Level: LogLevelInfo, Message: "m", Attrs: []LogAttr{Any("raw", b)}})
+	if got != `{"level":"INFO","msg":"m","raw":"Chv/"}`+"\n" {
+		t.Errorf("got %s", got)
+	}
+}
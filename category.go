@@ -0,0 +1,26 @@
+package rlog
+
+// The well-known categories, each is registered independently like the
+// default logger, ex:
+//
+//	RegisterLogHandler(KEY_ACCESS_LOGGER, accessHandler)
+//	RegisterLogHandler(KEY_AUDIT_LOGGER, auditHandler)
+const (
+	KEY_ACCESS_LOGGER = "access"
+	KEY_AUDIT_LOGGER  = "audit"
+	KEY_APP_LOGGER    = "app"
+)
+
+func GetAccessLogger() ILogger {
+	return GetCategoryLogger(KEY_ACCESS_LOGGER)
+}
+
+func GetAuditLogger() ILogger {
+	return GetCategoryLogger(KEY_AUDIT_LOGGER)
+}
+
+// Get the logger of the category, same as 'GetLogger()' with the category as
+// the name, so the categories are routed to the sinks by their registrations.
+func GetCategoryLogger(cat string) ILogger {
+	return GetLogger(cat)
+}
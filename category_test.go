@@ -0,0 +1,32 @@
+package rlog
+
+import "testing"
+
+func TestCategoriesAreIsolated(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	access, accessRecords := NewMemoryHandler(LogLevelInfo)
+	audit, auditRecords := NewMemoryHandler(LogLevelInfo)
+	jobs, jobsRecords := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandler(KEY_ACCESS_LOGGER, access)
+	RegisterLogHandler(KEY_AUDIT_LOGGER, audit)
+	RegisterLogHandler("jobs", jobs)
+
+	GetAccessLogger().Info("GET /")
+	GetAuditLogger().Info("login")
+	GetCategoryLogger("jobs").Info("run")
+
+	for name, rs := range map[string][]LogRecord{
+		"GET /": accessRecords(),
+		"login": auditRecords(),
+		"run":   jobsRecords(),
+	} {
+		if len(rs) != 1 || rs[0].Message != name {
+			t.Errorf("got %v, want %s only", rs, name)
+		}
+	}
+
+	if _, ok := LookupLogger(KEY_APP_LOGGER); ok {
+		t.Error("got the unregistered app category registered")
+	}
+}
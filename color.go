@@ -0,0 +1,67 @@
+package rlog
+
+import (
+	"io"
+	"os"
+)
+
+// When to colorize the output.
+type ColorMode int8
+
+const (
+	ColorNever ColorMode = iota
+
+	// Colorize if the writer is a terminal.
+	ColorAuto
+
+	ColorAlways
+)
+
+// The ANSI escape codes of the level colors.
+const (
+	ANSI_RESET  = "\x1b[0m"
+	ANSI_GRAY   = "\x1b[90m"
+	ANSI_RED    = "\x1b[31m"
+	ANSI_GREEN  = "\x1b[32m"
+	ANSI_YELLOW = "\x1b[33m"
+)
+
+func (m ColorMode) enabled(w io.Writer) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorAuto:
+		return isTerminal(w)
+	default:
+		return false
+	}
+}
+
+// Report whether 'w' is a character device, ex: a terminal. It's a rough check
+// without the platform specific ioctl, but good enough for the colors.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func levelColor(l LogLevel) string {
+	switch {
+	case l >= LogLevelError:
+		return ANSI_RED
+	case l >= LogLevelWarn:
+		return ANSI_YELLOW
+	case l >= LogLevelInfo:
+		return ANSI_GREEN
+	default:
+		return ANSI_GRAY
+	}
+}
@@ -0,0 +1,39 @@
+package rlog
+
+import "context"
+
+type loggerKey struct{}
+
+// Return a copy of 'ctx' carrying the logger, retrieve it with
+// 'LoggerFromContext()'.
+func ContextWithLogger(ctx context.Context, l ILogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// Return the logger carried by 'ctx', or the default logger if absent.
+func LoggerFromContext(ctx context.Context) ILogger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(ILogger); ok {
+			return l
+		}
+	}
+
+	return GetDefaultLogger()
+}
+
+// The function extracts the attributes from the context, ex: the trace and span
+// IDs of OpenTelemetry.
+type ContextExtractor func(ctx context.Context) []LogAttr
+
+var contextExtractor atomicBox[ContextExtractor]
+
+// Set the extractor called by the '*Ctx' methods, and the extracted attributes
+// are appended to the record, outside any group. Pass nil to remove it, which
+// is the default.
+func SetContextExtractor(fn ContextExtractor) {
+	contextExtractor.Store(fn)
+}
+
+func loadContextExtractor() ContextExtractor {
+	return contextExtractor.Load()
+}
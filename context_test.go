@@ -0,0 +1,70 @@
+package rlog
+
+import (
+	"context"
+	"testing"
+)
+
+type ctxKey struct{}
+
+func TestCtxMethodsPassTheContext(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelDebug)
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+
+	logger.DebugCtx(ctx, "a")
+	logger.InfoCtx(ctx, "b")
+	logger.WarnCtx(ctx, "c")
+	logger.ErrorCtx(ctx, "d")
+	logger.Info("e")
+
+	rs := snapshot()
+	if len(rs) != 5 {
+		t.Fatalf("got %d records, want 5", len(rs))
+	}
+	for _, r := range rs[:4] {
+		if r.Context.Value(ctxKey{}) != "v" {
+			t.Errorf("got no context value in %q", r.Message)
+		}
+	}
+	if rs[4].Context == nil {
+		t.Error("got nil context without ctx")
+	}
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	LoggerFromContext(ContextWithLogger(context.Background(), logger)).Info("a")
+
+	if rs := snapshot(); len(rs) != 1 {
+		t.Errorf("got %d records, want 1", len(rs))
+	}
+	if LoggerFromContext(context.Background()) == nil {
+		t.Error("got nil default logger")
+	}
+}
+
+func TestContextExtractor(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	SetContextExtractor(func(ctx context.Context) []LogAttr {
+		if id, ok := ctx.Value(ctxKey{}).(string); ok {
+			return []LogAttr{Str("trace_id", id)}
+		}
+		return nil
+	})
+	defer SetContextExtractor(nil)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "abc")
+	logger.WithGroup("g").InfoCtx(ctx, "a", "k", 1)
+	logger.Info("b")
+
+	rs := snapshot()
+	if len(rs) != 2 || len(rs[0].Attrs) != 2 ||
+		rs[0].Attrs[1] != (LogAttr{Key: "trace_id", Value: "abc"}) {
+		t.Errorf("got %v, want the trace ID outside the group", rs)
+	}
+	if len(rs[1].Attrs) != 0 {
+		t.Errorf("got %v, want no attrs without ctx", rs[1].Attrs)
+	}
+}
@@ -0,0 +1,56 @@
+package rlog
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// The counts of the records emitted at the built-in levels, indexed by the
+// level minus 'LogLevelDebug'.
+var levelCounts [LogLevelFatal - LogLevelDebug + 1]int64
+
+// The counts of the records emitted at other levels, ex: by 'Log()'.
+var otherLevelCounts sync.Map // map[LogLevel]*int64
+
+func countLevel(l LogLevel) {
+	if l >= LogLevelDebug && l <= LogLevelFatal {
+		atomic.AddInt64(&levelCounts[l-LogLevelDebug], 1)
+		return
+	}
+
+	v, ok := otherLevelCounts.Load(l)
+	if !ok {
+		v, _ = otherLevelCounts.LoadOrStore(l, new(int64))
+	}
+
+	atomic.AddInt64(v.(*int64), 1)
+}
+
+// Return the counts of the records emitted by the loggers at each level, since
+// the start or the last 'ResetLevelCounts()'. The disabled records are not
+// counted.
+func LevelCounts() map[LogLevel]int64 {
+	counts := make(map[LogLevel]int64, len(levelCounts))
+
+	for i := range levelCounts {
+		counts[LogLevelDebug+LogLevel(i)] = atomic.LoadInt64(&levelCounts[i])
+	}
+
+	otherLevelCounts.Range(func(k, v any) bool {
+		counts[k.(LogLevel)] = atomic.LoadInt64(v.(*int64))
+		return true
+	})
+
+	return counts
+}
+
+func ResetLevelCounts() {
+	for i := range levelCounts {
+		atomic.StoreInt64(&levelCounts[i], 0)
+	}
+
+	otherLevelCounts.Range(func(k, v any) bool {
+		atomic.StoreInt64(v.(*int64), 0)
+		return true
+	})
+}
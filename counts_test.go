@@ -0,0 +1,37 @@
+package rlog
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLevelCounts(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelInfo)
+	ResetLevelCounts()
+	defer ResetLevelCounts()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			logger.Debug("disabled")
+			logger.Info("a")
+			logger.Error("b")
+			logger.Log(LogLevel(9), "c")
+		}()
+	}
+	wg.Wait()
+
+	counts := LevelCounts()
+	if counts[LogLevelDebug] != 0 || counts[LogLevelInfo] != 4 ||
+		counts[LogLevelError] != 4 || counts[LogLevel(9)] != 4 {
+		t.Errorf("got counts %v", counts)
+	}
+
+	ResetLevelCounts()
+	if counts := LevelCounts(); counts[LogLevelInfo] != 0 || counts[LogLevel(9)] != 0 {
+		t.Errorf("got counts %v after the reset", counts)
+	}
+}
@@ -0,0 +1,75 @@
+package rlog
+
+import "sync/atomic"
+
+// The value of the attribute created by 'DebugAttr()'.
+type debugOnly struct {
+	value any
+}
+
+// Skip the lookups if 'DebugAttr()' is never called.
+var hasDebugAttrs int32
+
+// Return an attribute which is attached to the debug records only, ex: the full
+// request body bound by 'With()' which is too noisy for the info records:
+//
+//	logger = logger.With(rlog.DebugAttr("body", body))
+//	logger.Debug("request") // With "body".
+//	logger.Info("request")  // Without "body".
+func DebugAttr(key string, v any) LogAttr {
+	atomic.StoreInt32(&hasDebugAttrs, 1)
+	return LogAttr{Key: key, Value: debugOnly{value: v}}
+}
+
+// Unwrap the debug attributes of the record at 'level', or drop them if it's
+// not a debug record. The 'attrs' are modified in place, and the groups
+// containing the debug attributes are copied.
+func applyDebugAttrs(attrs []LogAttr, level LogLevel) []LogAttr {
+	if atomic.LoadInt32(&hasDebugAttrs) == 0 {
+		return attrs
+	}
+
+	return filterDebugAttrs(attrs, level <= LogLevelDebug)
+}
+
+func filterDebugAttrs(attrs []LogAttr, keep bool) []LogAttr {
+	out := attrs[:0]
+
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case debugOnly:
+			if !keep {
+				continue
+			}
+			attr.Value = v.value
+		case []LogAttr:
+			if hasDebugAttr(v) {
+				attr.Value = filterDebugAttrs(concatAttrs(v, nil), keep)
+			}
+		}
+
+		out = append(out, attr)
+	}
+
+	// Not to retain the dropped values.
+	for i := len(out); i < len(attrs); i++ {
+		attrs[i] = LogAttr{}
+	}
+
+	return out
+}
+
+func hasDebugAttr(attrs []LogAttr) bool {
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case debugOnly:
+			return true
+		case []LogAttr:
+			if hasDebugAttr(v) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
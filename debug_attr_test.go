@@ -0,0 +1,40 @@
+package rlog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDebugAttrOnlyOnDebugRecords(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelDebug)
+
+	logger = logger.With(DebugAttr("body", "{}"), "k", 1).
+		WithGroup("g").With(DebugAttr("raw", "x"), "n", 2)
+
+	logger.Info("a")
+	logger.Debug("b", DebugAttr("extra", true))
+	logger.Info("c")
+
+	rs := snapshot()
+	if len(rs) != 3 {
+		t.Fatalf("got %d records, want 3", len(rs))
+	}
+
+	info := map[string]any{
+		KEY_LEVEL: "INFO", "k": 1, "g": map[string]any{"n": 2},
+	}
+	debug := map[string]any{
+		KEY_LEVEL: "DEBUG", "body": "{}", "k": 1,
+		"g": map[string]any{"raw": "x", "n": 2, "extra": true},
+	}
+
+	for i, want := range []map[string]any{info, debug, info} {
+		got := rs[i].ToMap()
+		delete(got, KEY_TIME)
+		delete(got, KEY_MESSAGE)
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package rlog
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	deprecatedKeys   sync.Map // map[string]string, the old key to the new one.
+	deprecatedWarned sync.Map // map[string]struct{}, the old keys warned.
+
+	// Skip the lookups if no key is deprecated.
+	hasDeprecatedKeys int32
+)
+
+// Register the 'old' key as deprecated, the loggers rewrite it to 'new' in the
+// attributes passed to the log methods and 'With()', and a warning is written
+// to the stderr once per old key. Ex: to find the lingering old keys when
+// renaming the keys in a large codebase.
+func RegisterDeprecatedKey(old, new string) {
+	deprecatedKeys.Store(old, new)
+	atomic.StoreInt32(&hasDeprecatedKeys, 1)
+}
+
+// Return the key of the attribute passed to the loggers, renamed if deprecated
+// then normalized, see 'SetKeyNormalizer()'.
+func renameKey(key string) string {
+	return normalizeKey(deprecatedKey(key))
+}
+
+// Return the new key if 'key' is deprecated, or 'key' itself.
+func deprecatedKey(key string) string {
+	if atomic.LoadInt32(&hasDeprecatedKeys) == 0 {
+		return key
+	}
+
+	v, ok := deprecatedKeys.Load(key)
+	if !ok {
+		return key
+	}
+
+	if _, warned := deprecatedWarned.LoadOrStore(key, struct{}{}); !warned {
+		fmt.Fprintf(stderr, "rlog: key %q is deprecated, use %q instead\n",
+			key, v)
+	}
+
+	return v.(string)
+}
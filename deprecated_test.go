@@ -0,0 +1,32 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeprecatedKeyIsRenamedAndWarnedOnce(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+	buf := captureStderr(t)
+
+	RegisterDeprecatedKey("user_id", "uid")
+	t.Cleanup(func() {
+		deprecatedKeys.Delete("user_id")
+		deprecatedWarned.Delete("user_id")
+	})
+
+	logger.With("user_id", 1).Info("a", Int("user_id", 2), map[string]any{"user_id": 3})
+	logger.Info("b", "user_id", 4)
+
+	for _, r := range snapshot() {
+		for _, a := range r.Attrs {
+			if a.Key != "uid" {
+				t.Errorf("got key %q in %q, want uid", a.Key, r.Message)
+			}
+		}
+	}
+
+	if n := strings.Count(buf.String(), `rlog: key "user_id" is deprecated, use "uid" instead`); n != 1 {
+		t.Errorf("got stderr %q, want one warning", buf.String())
+	}
+}
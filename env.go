@@ -0,0 +1,123 @@
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// The environment variables read by 'ConfigureFromEnv()'.
+const (
+	ENV_LEVEL  = "RLOG_LEVEL"  // debug|info|warn|error, "info" by default.
+	ENV_FORMAT = "RLOG_FORMAT" // json|text, "text" by default.
+	ENV_OUTPUT = "RLOG_OUTPUT" // stdout|stderr|<path>, "stderr" by default.
+)
+
+var ErrDefaultRegistered = errors.New("rlog: default handler already registered")
+
+// The handler writing to the file opened by 'ConfigureFromEnv()', which closes
+// the file in 'Close()'.
+type envFileHandler struct {
+	inner LogHandler
+	f     *os.File
+}
+
+func (h *envFileHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *envFileHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+// The level of the built-in handlers is cached by the loggers if fixed.
+func (h *envFileHandler) StaticLevel() (LogLevel, bool) {
+	if s, ok := h.inner.(StaticLeveler); ok {
+		return s.StaticLevel()
+	}
+
+	return 0, false
+}
+
+func (h *envFileHandler) Handle(r LogRecord) {
+	h.inner.Handle(r)
+}
+
+func (h *envFileHandler) Flush() error {
+	if err := flushHandler(h.inner); err != nil {
+		return err
+	}
+
+	return h.f.Sync()
+}
+
+func (h *envFileHandler) Close() error {
+	var errs multiError
+
+	if err := flushHandler(h.inner); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := h.f.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs.orNil()
+}
+
+// Create a handler from the environment variables 'ENV_LEVEL', 'ENV_FORMAT'
+// and 'ENV_OUTPUT', and register it as the default handler. Ex:
+//
+//	RLOG_LEVEL=debug RLOG_FORMAT=json RLOG_OUTPUT=/var/log/app.log ./app
+//
+// The output file is appended, and closed by 'CloseAll()'.
+func ConfigureFromEnv() error {
+	level := LogLevelInfo
+
+	if s := os.Getenv(ENV_LEVEL); s != "" {
+		l, err := ParseLevel(s)
+		if err != nil {
+			return fmt.Errorf("rlog: invalid %s %q, want debug, info, warn or "+
+				"error", ENV_LEVEL, s)
+		}
+		level = l
+	}
+
+	format := strings.ToLower(strings.TrimSpace(os.Getenv(ENV_FORMAT)))
+
+	var newHandler func(io.Writer, Leveler) LogHandler
+	switch format {
+	case "", "text":
+		newHandler = NewTextHandler
+	case "json":
+		newHandler = NewJSONHandler
+	default:
+		return fmt.Errorf("rlog: invalid %s %q, want json or text",
+			ENV_FORMAT, format)
+	}
+
+	var h LogHandler
+	switch output := strings.TrimSpace(os.Getenv(ENV_OUTPUT)); output {
+	case "", "stderr":
+		h = newHandler(os.Stderr, level)
+	case "stdout":
+		h = newHandler(os.Stdout, level)
+	default:
+		f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			// The error of 'os' has no prefix, ex: "open app.log: ...".
+			return fmt.Errorf("rlog: invalid %s: %w", ENV_OUTPUT, err)
+		}
+
+		h = &envFileHandler{inner: newHandler(f, level), f: f}
+	}
+
+	if !SetDefaultLogHandler(h) {
+		closeHandler(h)
+		return ErrDefaultRegistered
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package rlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigureFromEnv(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+	UnsetDefaultLogHandler()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv(ENV_LEVEL, "warn")
+	t.Setenv(ENV_FORMAT, "JSON")
+	t.Setenv(ENV_OUTPUT, path)
+
+	if err := ConfigureFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ConfigureFromEnv(); err != ErrDefaultRegistered {
+		t.Errorf("got %v, want ErrDefaultRegistered", err)
+	}
+
+	// The optional interfaces of the inner handler are kept.
+	v, _ := loggers.Load(KEY_DEFAULT_LOGGER)
+	h := v.(*registration).h
+	if l, ok := h.(StaticLeveler).StaticLevel(); !ok || l != LogLevelWarn {
+		t.Errorf("got static level %v %v, want WARN", l, ok)
+	}
+
+	logger := GetDefaultLogger()
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	if err := CloseAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); strings.Count(s, "\n") != 1 ||
+		!strings.Contains(s, `"level":"WARN","msg":"kept"`) {
+		t.Errorf("got %q", s)
+	}
+}
+
+func TestConfigureFromEnvRejectsInvalidValues(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+	UnsetDefaultLogHandler()
+
+	for _, env := range [][2]string{{ENV_LEVEL, "loud"}, {ENV_FORMAT, "xml"}} {
+		t.Run(env[0], func(t *testing.T) {
+			t.Setenv(env[0], env[1])
+
+			err := ConfigureFromEnv()
+			if err == nil || !strings.Contains(err.Error(), env[0]) ||
+				strings.Count(err.Error(), "rlog:") != 1 {
+				t.Errorf("got %v, want the error of %s", err, env[0])
+			}
+		})
+	}
+
+	t.Setenv(ENV_OUTPUT, filepath.Join(t.TempDir(), "missing", "app.log"))
+	if err := ConfigureFromEnv(); !errors.Is(err, os.ErrNotExist) ||
+		strings.Count(err.Error(), "rlog:") != 1 {
+		t.Errorf("got %v, want not exist", err)
+	}
+	if _, ok := LookupLogger(KEY_DEFAULT_LOGGER); ok {
+		t.Error("got registered after the errors")
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/leoadonia/rlog
 
 go 1.18
+
+require golang.org/x/sys v0.15.0
@@ -0,0 +1,353 @@
+package rlog
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The keys of the built-in fields in the records written by the built-in
+// handlers. The time is omitted if 'LogRecord.Time' is zero.
+const (
+	KEY_TIME    = "time"
+	KEY_LEVEL   = "level"
+	KEY_MESSAGE = "msg"
+	KEY_SOURCE  = "source"
+)
+
+// The options of the built-in handlers, the zero value is valid.
+type HandlerOptions struct {
+	// The minimum level of the records to be handled, 'LogLevelInfo' if nil.
+	// Use a '*LevelVar' to change the level at runtime.
+	Level Leveler
+
+	// Whether to colorize the level with the ANSI colors, only used by the text
+	// handler. Never colorized by default.
+	Color ColorMode
+
+	// Capture and write the source location of the call sites.
+	AddSource bool
+
+	// Collapse the attributes with the same key in the same group, the last
+	// value wins and the position of the first one is kept. Ex: the key bound
+	// by 'With()' and set again in the log method.
+	DedupKeys bool
+
+	// Called for each attribute except the groups before it's written, ex: to
+	// redact, rename or drop it. The 'groups' are the names of the groups
+	// containing the attribute, and the returned attribute is omitted if its
+	// key is empty. Never modify the 'groups'.
+	ReplaceAttr func(groups []string, a LogAttr) LogAttr
+
+	// If the writer implements 'WriteSyncer', it's synced after writing the
+	// records with level not less than this, ex: to keep the error records
+	// on the disk even if the app crashes. Never synced if nil.
+	SyncLevel Leveler
+
+	// How the JSON handler writes the 'time.Duration' values, in the string
+	// form by default, ex: "1.5s".
+	DurationFormat DurationFormat
+
+	// The key and layout of the record time written by the JSON handler, ex:
+	// "@timestamp" and 'time.RFC3339'. The 'KEY_TIME' and 'time.RFC3339Nano'
+	// are used if empty. Use 'TIME_FORMAT_EPOCH_MILLIS' to write the integer
+	// milliseconds since the Unix epoch.
+	TimeKey    string
+	TimeFormat string
+
+	// Omit the attributes with the empty values in the JSON handler, ex: to
+	// save the bandwidth. The nil, empty string and zero time are empty.
+	OmitEmpty bool
+
+	// Write the record time and the 'time.Time' attribute values in their own
+	// locations, ex: the local time. They are converted to UTC by default, so
+	// the records of the hosts in different time zones are consistent.
+	LocalTime bool
+
+	// The number of digits after the decimal point of the float values written
+	// by the text and logfmt handlers, ex: 2 writes 3.14159 as "3.14" and 0
+	// writes it as "3". The shortest form which round-trips is written if nil
+	// or negative.
+	FloatPrecision *int
+
+	// Truncate the slice and array values to the first N elements, and a
+	// marker of the omitted ones is appended, ex: "…(+7 more)", so the size of
+	// the records is bounded. The '[]byte' values are not truncated. Never
+	// truncated if not positive.
+	MaxSliceLen int
+
+	// Called when the handler fails to write or sync, ex: the disk is full.
+	// The errors are written to the stderr if nil.
+	OnError func(err error)
+}
+
+func (o *HandlerOptions) onError(err error) {
+	if o != nil && o.OnError != nil {
+		o.OnError(err)
+		return
+	}
+
+	reportError(err)
+}
+
+// Write the error of the handler to the stderr.
+func reportError(err error) {
+	fmt.Fprintf(stderr, "rlog: handler failed: %v\n", err)
+}
+
+// The special 'HandlerOptions.TimeFormat' for the Unix epoch milliseconds.
+const TIME_FORMAT_EPOCH_MILLIS = "epoch_millis"
+
+type DurationFormat int8
+
+const (
+	// Ex: "1.5s".
+	DurationAsString DurationFormat = iota
+
+	// The integer milliseconds, ex: 1500.
+	DurationAsMillis
+)
+
+func (o *HandlerOptions) durationValue(d time.Duration) any {
+	if o != nil && o.DurationFormat == DurationAsMillis {
+		return d.Milliseconds()
+	}
+
+	return d.String()
+}
+
+// Convert the time to UTC unless 'LocalTime' is enabled.
+func (o *HandlerOptions) timeValue(t time.Time) time.Time {
+	if o != nil && o.LocalTime {
+		return t
+	}
+
+	return t.UTC()
+}
+
+// Return the 'FloatPrecision', false if the shortest form is written.
+func (o *HandlerOptions) floatPrecision() (int, bool) {
+	if o == nil || o.FloatPrecision == nil || *o.FloatPrecision < 0 {
+		return 0, false
+	}
+
+	return *o.FloatPrecision, true
+}
+
+// Format the value by 'LocalTime' and 'FloatPrecision' for the text handlers,
+// others are returned as is.
+func (o *HandlerOptions) textValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		return o.timeValue(v)
+	case float64:
+		if prec, ok := o.floatPrecision(); ok {
+			return strconv.FormatFloat(v, 'f', prec, 64)
+		}
+	case float32:
+		if prec, ok := o.floatPrecision(); ok {
+			return strconv.FormatFloat(float64(v), 'f', prec, 32)
+		}
+	}
+
+	return o.sliceValue(v)
+}
+
+// Truncate the slice or array value by 'MaxSliceLen', others are returned as
+// is.
+func (o *HandlerOptions) sliceValue(v any) any {
+	if o == nil || o.MaxSliceLen <= 0 || v == nil {
+		return v
+	}
+
+	if _, ok := v.([]byte); ok {
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return v
+	}
+
+	n := rv.Len()
+	if n <= o.MaxSliceLen {
+		return v
+	}
+
+	truncated := make([]any, 0, o.MaxSliceLen+1)
+	for i := 0; i < o.MaxSliceLen; i++ {
+		truncated = append(truncated, rv.Index(i).Interface())
+	}
+
+	return append(truncated, fmt.Sprintf("…(+%d more)", n-o.MaxSliceLen))
+}
+
+// Whether the attribute value is omitted by 'OmitEmpty'.
+func (o *HandlerOptions) omitted(v any) bool {
+	if o == nil || !o.OmitEmpty {
+		return false
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case time.Time:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// The writer could commit the written data to the stable storage, ex: the
+// '*os.File'.
+type WriteSyncer interface {
+	io.Writer
+	Sync() error
+}
+
+func (o *HandlerOptions) replaceAttr(groups []string, a LogAttr) LogAttr {
+	if o == nil || o.ReplaceAttr == nil {
+		return a
+	}
+
+	return o.ReplaceAttr(groups, a)
+}
+
+// Return a new slice, so the 'groups' passed to 'ReplaceAttr' of the sibling
+// attributes are never modified.
+func appendGroup(groups []string, name string) []string {
+	return append(groups[:len(groups):len(groups)], name)
+}
+
+// The optional interface of the handlers which want the 'LogRecord.Source'.
+//
+// Capturing the source location is not free, so the loggers only capture it if
+// the handler implements this and 'CaptureSource()' returns true.
+type SourceCapturer interface {
+	CaptureSource() bool
+}
+
+func captureSource(h LogHandler) bool {
+	c, ok := h.(SourceCapturer)
+	return ok && c.CaptureSource()
+}
+
+// The common part of the built-in handlers which write the formatted records
+// to an 'io.Writer'.
+type baseHandler struct {
+	mu   sync.Mutex // Guards the writes to 'w'.
+	w    io.Writer
+	opts HandlerOptions
+}
+
+func (h *baseHandler) init(w io.Writer, opts *HandlerOptions) {
+	h.w = w
+
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	if h.opts.Level == nil {
+		h.opts.Level = LogLevelInfo
+	}
+}
+
+func (h *baseHandler) Enabled(l LogLevel) bool {
+	return l >= h.opts.Level.Level()
+}
+
+// The level is static unless it's a '*LevelVar' or other dynamic 'Leveler'.
+func (h *baseHandler) StaticLevel() (LogLevel, bool) {
+	l, ok := h.opts.Level.(LogLevel)
+	return l, ok
+}
+
+func (h *baseHandler) CaptureSource() bool {
+	return h.opts.AddSource
+}
+
+// Return the attributes of the record to be written.
+func (h *baseHandler) attrsOf(r LogRecord) []LogAttr {
+	if h.opts.DedupKeys {
+		return dedupAttrs(r.Attrs)
+	}
+
+	return r.Attrs
+}
+
+// Write the formatted record at level 'l'.
+func (h *baseHandler) write(l LogLevel, b []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := h.w.Write(b); err != nil {
+		h.opts.onError(err)
+		return
+	}
+
+	if h.opts.SyncLevel != nil && l >= h.opts.SyncLevel.Level() {
+		if s, ok := h.w.(WriteSyncer); ok {
+			if err := s.Sync(); err != nil {
+				h.opts.onError(err)
+			}
+		}
+	}
+}
+
+// The optional interface of the handlers buffering the records, ex: the async
+// handler. 'Flush()' writes the buffered records to the sink.
+type Flusher interface {
+	Flush() error
+}
+
+// The optional interface of the handlers holding the resources, ex: the file
+// handler. 'Close()' flushes the buffered records and releases the resources,
+// the records handled after that may be dropped.
+//
+// The wrapper handlers, ex: the async handler, close their inner handlers too.
+type Closer interface {
+	Close() error
+}
+
+func flushHandler(h LogHandler) error {
+	if f, ok := h.(Flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+func closeHandler(h LogHandler) error {
+	if c, ok := h.(Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// The errors of several handlers, ex: returned by 'CloseAll()'.
+type multiError []error
+
+func (e multiError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Return nil if there is no error, so the result could be compared with nil.
+func (e multiError) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -0,0 +1,146 @@
+package rlog
+
+import (
+	"errors"
+	"sync"
+)
+
+// What the async handler does when its buffer is full.
+type AsyncPolicy int8
+
+const (
+	// Block the caller until there is room in the buffer.
+	AsyncPolicyBlock AsyncPolicy = iota
+
+	// Drop the record immediately.
+	AsyncPolicyDrop
+)
+
+var ErrHandlerClosed = errors.New("rlog: handler closed")
+
+// The handler enqueues the records to a buffered channel, and a background
+// goroutine drains it to the inner handler.
+type asyncHandler struct {
+	inner  LogHandler
+	policy AsyncPolicy
+	ch     chan asyncItem
+	done   chan struct{}
+
+	mu     sync.RWMutex // Guards 'closed', and the sends to 'ch'.
+	closed bool
+}
+
+// The item in the buffer, either a record or a flush request.
+type asyncItem struct {
+	r       LogRecord
+	flushed chan struct{} // Closed once the items before it are handled.
+}
+
+// Same as 'NewAsyncHandlerWithPolicy()' with 'AsyncPolicyBlock'.
+func NewAsyncHandler(inner LogHandler, bufferSize int) (LogHandler, func() error) {
+	return NewAsyncHandlerWithPolicy(inner, bufferSize, AsyncPolicyBlock)
+}
+
+// Create a handler which calls the 'inner.Handle()' in a background goroutine,
+// so the slow sinks don't block the caller.
+//
+// The returned function is same as the 'Close()' of the handler, it flushes the
+// buffered records, stops the goroutine, and closes 'inner' if it implements
+// 'Closer'. The records handled after that are dropped.
+func NewAsyncHandlerWithPolicy(
+	inner LogHandler,
+	bufferSize int,
+	policy AsyncPolicy,
+) (LogHandler, func() error) {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	h := &asyncHandler{
+		inner:  inner,
+		policy: policy,
+		ch:     make(chan asyncItem, bufferSize),
+		done:   make(chan struct{}),
+	}
+
+	go h.run()
+
+	return h, h.Close
+}
+
+func (h *asyncHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *asyncHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *asyncHandler) Handle(r LogRecord) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return
+	}
+
+	// The attributes are reused once this returns.
+	r = r.Clone()
+
+	if h.policy == AsyncPolicyDrop {
+		select {
+		case h.ch <- asyncItem{r: r}:
+		default:
+		}
+	} else {
+		h.ch <- asyncItem{r: r}
+	}
+}
+
+func (h *asyncHandler) run() {
+	defer close(h.done)
+
+	for item := range h.ch {
+		if item.flushed != nil {
+			close(item.flushed)
+		} else {
+			handleSafely(h.inner, item.r)
+		}
+	}
+}
+
+// Wait until the records handled before are passed to the inner handler, then
+// flush the inner handler if it implements 'Flusher'.
+func (h *asyncHandler) Flush() error {
+	flushed := make(chan struct{})
+
+	h.mu.RLock()
+
+	if h.closed {
+		h.mu.RUnlock()
+		return ErrHandlerClosed
+	}
+
+	// Never dropped even with 'AsyncPolicyDrop'.
+	h.ch <- asyncItem{flushed: flushed}
+	h.mu.RUnlock()
+
+	<-flushed
+	return flushHandler(h.inner)
+}
+
+func (h *asyncHandler) Close() error {
+	h.mu.Lock()
+
+	if h.closed {
+		h.mu.Unlock()
+		return ErrHandlerClosed
+	}
+
+	h.closed = true
+	close(h.ch)
+	h.mu.Unlock()
+
+	<-h.done
+	return closeHandler(h.inner)
+}
@@ -0,0 +1,83 @@
+package rlog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// The handler blocks until 'release' is closed.
+type blockingHandler struct {
+	release chan struct{}
+}
+
+func (h blockingHandler) Enabled(LogLevel) bool { return true }
+
+func (h blockingHandler) Handle(LogRecord) { <-h.release }
+
+func TestAsyncHandlerDrainsOnClose(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h, closeFn := NewAsyncHandler(mem, 4)
+
+	attrs := []LogAttr{Int("i", 0)}
+	for i := 0; i < 10; i++ {
+		attrs[0].Value = i
+		h.Handle(LogRecord{Level: LogLevelInfo, Message: fmt.Sprint(i), Attrs: attrs})
+	}
+
+	if err := closeFn(); err != nil {
+		t.Fatal(err)
+	}
+	if err := closeFn(); err != ErrHandlerClosed {
+		t.Errorf("got %v, want ErrHandlerClosed", err)
+	}
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "dropped"})
+
+	rs := snapshot()
+	if len(rs) != 10 {
+		t.Fatalf("got %d records, want 10", len(rs))
+	}
+	for i, r := range rs {
+		if r.Message != fmt.Sprint(i) || r.Attrs[0].Value != i {
+			t.Errorf("got record %d %v", i, r)
+		}
+	}
+}
+
+func TestAsyncHandlerDropPolicyNeverBlocks(t *testing.T) {
+	inner := blockingHandler{release: make(chan struct{})}
+	h, closeFn := NewAsyncHandlerWithPolicy(inner, 1, AsyncPolicyDrop)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 10; i++ {
+			h.Handle(LogRecord{Level: LogLevelInfo})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("got blocked by the full buffer")
+	}
+
+	close(inner.release)
+	closeFn()
+}
+
+func TestAsyncHandlerFlush(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h, closeFn := NewAsyncHandler(mem, 100)
+	defer closeFn()
+
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "a"})
+	if err := h.(Flusher).Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if rs := snapshot(); len(rs) != 1 {
+		t.Errorf("got %d records after the flush, want 1", len(rs))
+	}
+}
@@ -0,0 +1,206 @@
+package rlog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The number of the full batches waiting for the background goroutine, the
+// records beyond that are dropped, so a slow sink never grows the memory
+// without bound.
+const maxPendingBatches = 8
+
+// The optional interface of the handlers which handle the records in batches
+// more efficiently, ex: the network sinks.
+type Batcher interface {
+	BatchHandle(rs []LogRecord)
+}
+
+// The handler accumulates the records and passes them to the inner handler in
+// batches.
+type batchHandler struct {
+	inner    LogHandler
+	maxBatch int
+	stop     chan struct{}
+	done     chan struct{}
+	full     chan struct{} // Wakes the background goroutine up to flush.
+	async    bool          // Whether the background goroutine is running.
+
+	mu      sync.Mutex // Guards 'records', 'dropped' and 'closed'.
+	records []LogRecord
+	dropped int
+	closed  bool
+
+	flushMu sync.Mutex // Serializes the flushes, so the batches are in order.
+}
+
+// Create a handler which passes the records to 'inner' in batches, once
+// 'maxBatch' records are accumulated, or every 'flushInterval'.
+//
+// The batches are passed to 'BatchHandle()' if 'inner' implements 'Batcher',
+// otherwise to 'Handle()' one by one. The full batches are passed by the
+// background goroutine, so a slow sink never blocks the callers, and the
+// records are dropped once 'maxPendingBatches' are waiting. If 'flushInterval'
+// is not positive, there is no background goroutine, and the full batches are
+// passed by the callers. The returned function is same as the
+// 'Close()' of the handler, it flushes the remaining records, stops the timer,
+// and closes 'inner' if it implements 'Closer'.
+func NewBatchHandler(
+	inner LogHandler,
+	maxBatch int,
+	flushInterval time.Duration,
+) (LogHandler, func() error) {
+	if maxBatch < 1 {
+		maxBatch = 1
+	}
+
+	h := &batchHandler{
+		inner:    inner,
+		maxBatch: maxBatch,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		full:     make(chan struct{}, 1),
+		records:  make([]LogRecord, 0, maxBatch),
+	}
+
+	if flushInterval > 0 {
+		h.async = true
+		go h.run(flushInterval)
+	} else {
+		close(h.done)
+	}
+
+	return h, h.Close
+}
+
+func (h *batchHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *batchHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *batchHandler) Handle(r LogRecord) {
+	// The attributes are reused once this returns.
+	r = r.Clone()
+
+	h.mu.Lock()
+
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+
+	if h.async && len(h.records) >= h.maxBatch*maxPendingBatches {
+		h.dropped++
+		h.mu.Unlock()
+		return
+	}
+
+	h.records = append(h.records, r)
+	full := len(h.records) >= h.maxBatch
+	h.mu.Unlock()
+
+	if !full {
+		return
+	}
+
+	if !h.async {
+		h.flush()
+		return
+	}
+
+	select {
+	case h.full <- struct{}{}:
+	default: // Already woken up.
+	}
+}
+
+func (h *batchHandler) run(interval time.Duration) {
+	defer close(h.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.flush()
+		case <-h.full:
+			h.flush()
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// Pass the accumulated records to the inner handler.
+func (h *batchHandler) flush() {
+	h.flushMu.Lock()
+	defer h.flushMu.Unlock()
+
+	h.mu.Lock()
+	records, dropped := h.records, h.dropped
+	h.records = make([]LogRecord, 0, h.maxBatch)
+	h.dropped = 0
+	h.mu.Unlock()
+
+	if dropped > 0 {
+		reportError(fmt.Errorf("rlog: %d records dropped by the full batch backlog",
+			dropped))
+	}
+
+	for len(records) > 0 {
+		n := h.maxBatch
+		if n > len(records) {
+			n = len(records)
+		}
+
+		h.handleBatch(records[:n])
+		records = records[n:]
+	}
+}
+
+// Pass a batch to the inner handler, and a panic of it is recovered, since it
+// may be called by the background goroutine.
+func (h *batchHandler) handleBatch(batch []LogRecord) {
+	if b, ok := h.inner.(Batcher); ok {
+		defer func() {
+			if err := recover(); err != nil {
+				reportPanic(err)
+			}
+		}()
+
+		b.BatchHandle(batch)
+		return
+	}
+
+	for _, r := range batch {
+		handleSafely(h.inner, r)
+	}
+}
+
+func (h *batchHandler) Flush() error {
+	h.flush()
+	return flushHandler(h.inner)
+}
+
+func (h *batchHandler) Close() error {
+	h.mu.Lock()
+
+	if h.closed {
+		h.mu.Unlock()
+		return ErrHandlerClosed
+	}
+
+	h.closed = true
+	h.mu.Unlock()
+
+	close(h.stop)
+	<-h.done
+
+	h.flush()
+	return closeHandler(h.inner)
+}
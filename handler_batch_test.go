@@ -0,0 +1,77 @@
+package rlog
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// The handler keeps the sizes of the batches.
+type batchRecorder struct {
+	discardHandler
+
+	mu      sync.Mutex
+	batches []int
+	closed  bool
+}
+
+func (h *batchRecorder) BatchHandle(rs []LogRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.batches = append(h.batches, len(rs))
+}
+
+func (h *batchRecorder) Close() error {
+	h.closed = true
+	return nil
+}
+
+func (h *batchRecorder) sizes() []int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]int(nil), h.batches...)
+}
+
+func TestBatchHandlerWithoutInterval(t *testing.T) {
+	inner := &batchRecorder{}
+	h, closeFn := NewBatchHandler(inner, 3, 0)
+
+	for i := 0; i < 7; i++ {
+		h.Handle(LogRecord{Level: LogLevelInfo})
+	}
+
+	if got := inner.sizes(); len(got) != 2 || got[0] != 3 || got[1] != 3 {
+		t.Errorf("got batches %v, want 2 full batches", got)
+	}
+
+	if err := closeFn(); err != nil {
+		t.Fatal(err)
+	}
+	h.Handle(LogRecord{Level: LogLevelInfo})
+
+	if got := inner.sizes(); len(got) != 3 || got[2] != 1 || !inner.closed {
+		t.Errorf("got batches %v and closed %v, want the rest flushed",
+			got, inner.closed)
+	}
+	if err := closeFn(); err != ErrHandlerClosed {
+		t.Errorf("got %v, want ErrHandlerClosed", err)
+	}
+}
+
+func TestBatchHandlerFlushesEveryInterval(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h, closeFn := NewBatchHandler(mem, 100, 10*time.Millisecond)
+	defer closeFn()
+
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "a"})
+
+	deadline := time.Now().Add(time.Second)
+	for len(snapshot()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("got no record flushed by the timer")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
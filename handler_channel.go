@@ -0,0 +1,41 @@
+package rlog
+
+// The handler sends the records to a channel, ex: to stream them to a live
+// tail over a websocket.
+type channelHandler struct {
+	ch         chan<- LogRecord
+	level      Leveler
+	dropIfFull bool
+}
+
+// Create a handler which sends the records with level not less than 'level' to
+// 'ch'. If the channel is full, the record is dropped if 'dropIfFull' is true,
+// or the caller is blocked until the consumer receives one.
+//
+// The records are cloned, so the consumer could retain them.
+func NewChannelHandler(
+	ch chan<- LogRecord,
+	level Leveler,
+	dropIfFull bool,
+) LogHandler {
+	return &channelHandler{ch: ch, level: level, dropIfFull: dropIfFull}
+}
+
+func (h *channelHandler) Enabled(l LogLevel) bool {
+	return l >= h.level.Level()
+}
+
+func (h *channelHandler) Handle(r LogRecord) {
+	// The attributes are reused once this returns.
+	r = r.Clone()
+
+	if !h.dropIfFull {
+		h.ch <- r
+		return
+	}
+
+	select {
+	case h.ch <- r:
+	default:
+	}
+}
@@ -0,0 +1,53 @@
+package rlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChannelHandlerDropsIfFull(t *testing.T) {
+	ch := make(chan LogRecord, 1)
+	h := NewChannelHandler(ch, LogLevelInfo, true)
+
+	attrs := []LogAttr{Int("i", 0)}
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "a", Attrs: attrs})
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "dropped"})
+
+	// The records are cloned.
+	attrs[0].Value = 1
+
+	r := <-ch
+	if r.Message != "a" || r.Attrs[0].Value != 0 {
+		t.Errorf("got %v, want a with i=0", r)
+	}
+	if len(ch) != 0 {
+		t.Errorf("got %d records queued, want 0", len(ch))
+	}
+}
+
+func TestChannelHandlerBlocksIfFull(t *testing.T) {
+	ch := make(chan LogRecord, 1)
+	h := NewChannelHandler(ch, LogLevelInfo, false)
+
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "a"})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.Handle(LogRecord{Level: LogLevelInfo, Message: "b"})
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("got the full channel not blocking")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if r := <-ch; r.Message != "a" {
+		t.Errorf("got %q, want a", r.Message)
+	}
+	<-done
+	if r := <-ch; r.Message != "b" {
+		t.Errorf("got %q, want b", r.Message)
+	}
+}
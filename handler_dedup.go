@@ -0,0 +1,157 @@
+package rlog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// The key of the count of the records suppressed by the dedup handler.
+const KEY_REPEATED = "repeated"
+
+// The key of the identical records, with the same level and message.
+type messageKey struct {
+	level LogLevel
+	msg   string
+}
+
+type dedupEntry struct {
+	start      time.Time // When the window begins.
+	suppressed int
+}
+
+// The handler suppresses the identical records within a window.
+type dedupHandler struct {
+	inner  LogHandler
+	window time.Duration
+	now    func() time.Time // Replaceable in tests.
+
+	mu        sync.Mutex // Guards the fields below.
+	entries   map[messageKey]*dedupEntry
+	lastSweep time.Time
+}
+
+// Create a handler which passes the first one of the identical records, with
+// the same level and message, to 'inner', and suppresses the rest within
+// 'window'.
+//
+// Once the window closes, a summary record with the same level and message is
+// emitted if any record is suppressed, and the count is attached with
+// 'KEY_REPEATED', ex: "repeated=3". The windows are checked when the records
+// are handled, call 'Flush()' to emit the pending summaries.
+func NewDedupHandler(inner LogHandler, window time.Duration) LogHandler {
+	return &dedupHandler{
+		inner:   inner,
+		window:  window,
+		now:     time.Now,
+		entries: make(map[messageKey]*dedupEntry),
+	}
+}
+
+func (h *dedupHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *dedupHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *dedupHandler) Handle(r LogRecord) {
+	now := h.now()
+	key := messageKey{level: r.Level, msg: r.Message}
+
+	h.mu.Lock()
+
+	var summaries []LogRecord
+	if now.Sub(h.lastSweep) >= h.window {
+		summaries = h.sweep(now)
+		h.lastSweep = now
+	}
+
+	e, ok := h.entries[key]
+	if ok && now.Sub(e.start) >= h.window {
+		if e.suppressed > 0 {
+			summaries = append(summaries, summaryOf(key, e, now))
+		}
+		ok = false
+	}
+
+	if ok {
+		e.suppressed++
+	} else {
+		h.entries[key] = &dedupEntry{start: now}
+	}
+
+	h.mu.Unlock()
+
+	for _, s := range summaries {
+		h.inner.Handle(s)
+	}
+
+	if !ok {
+		h.inner.Handle(r)
+	}
+}
+
+// Remove the entries whose windows are closed before 'now', and return the
+// summaries of them. All the entries are removed if 'now' is zero.
+func (h *dedupHandler) sweep(now time.Time) []LogRecord {
+	var summaries []LogRecord
+
+	for key, e := range h.entries {
+		if !now.IsZero() && now.Sub(e.start) < h.window {
+			continue
+		}
+
+		if e.suppressed > 0 {
+			summaries = append(summaries, summaryOf(key, e, now))
+		}
+
+		delete(h.entries, key)
+	}
+
+	return summaries
+}
+
+func summaryOf(key messageKey, e *dedupEntry, now time.Time) LogRecord {
+	return LogRecord{
+		Time:    now,
+		Message: key.msg,
+		Attrs:   []LogAttr{{Key: KEY_REPEATED, Value: e.suppressed}},
+		Level:   key.level,
+		Context: context.Background(),
+	}
+}
+
+// Emit the summaries of all the pending windows, then flush the inner handler
+// if it implements 'Flusher'.
+func (h *dedupHandler) Flush() error {
+	now := h.now()
+
+	h.mu.Lock()
+	summaries := h.sweep(time.Time{})
+	h.mu.Unlock()
+
+	for _, s := range summaries {
+		s.Time = now
+		h.inner.Handle(s)
+	}
+
+	return flushHandler(h.inner)
+}
+
+// Flush the pending windows, then close the inner handler even if the flush
+// fails, so its resources are released.
+func (h *dedupHandler) Close() error {
+	var errs multiError
+
+	if err := h.Flush(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := closeHandler(h.inner); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs.orNil()
+}
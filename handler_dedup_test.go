@@ -0,0 +1,81 @@
+package rlog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupHandler(t *testing.T) {
+	inner, snapshot := NewMemoryHandler(LogLevelDebug)
+	h := NewDedupHandler(inner, time.Second).(*dedupHandler)
+
+	now := time.Unix(1000, 0)
+	h.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		h.Handle(LogRecord{Level: LogLevelError, Message: "conn refused"})
+	}
+	h.Handle(LogRecord{Level: LogLevelWarn, Message: "conn refused"})
+
+	now = now.Add(time.Second)
+	h.Handle(LogRecord{Level: LogLevelError, Message: "conn refused"})
+
+	rs := snapshot()
+	if len(rs) != 4 {
+		t.Fatalf("got %d records, want 4", len(rs))
+	}
+
+	summary := rs[2]
+	if summary.Message != "conn refused" || summary.Level != LogLevelError ||
+		summary.Attrs[0] != (LogAttr{Key: KEY_REPEATED, Value: 3}) {
+		t.Errorf("got summary %v, want 3 repeated", summary)
+	}
+	if rs[3].Message != "conn refused" || len(rs[3].Attrs) != 0 {
+		t.Errorf("got %v, want the record of the new window", rs[3])
+	}
+}
+
+func TestDedupHandlerFlushEmitsSummaries(t *testing.T) {
+	inner, snapshot := NewMemoryHandler(LogLevelDebug)
+	h := NewDedupHandler(inner, time.Hour)
+
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "a"})
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "a"})
+
+	if err := h.(Flusher).Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := snapshot()
+	if len(rs) != 2 || rs[1].Attrs[0].Value != 1 {
+		t.Errorf("got %v, want a summary of 1", rs)
+	}
+	if n := len(h.(*dedupHandler).entries); n != 0 {
+		t.Errorf("got %d entries after the flush, want 0", n)
+	}
+}
+
+// The handler fails the flushes, and records the calls.
+type flushFailingHandler struct {
+	lifecycleHandler
+}
+
+func (h *flushFailingHandler) Flush() error {
+	h.calls = append(h.calls, "flush")
+	return errors.New("flush failed")
+}
+
+func TestDedupCloseClosesInnerIfFlushFails(t *testing.T) {
+	inner := &flushFailingHandler{lifecycleHandler{err: errors.New("close failed")}}
+
+	err := NewDedupHandler(inner, time.Second).(*dedupHandler).Close()
+	if err == nil || !strings.Contains(err.Error(), "flush failed") ||
+		!strings.Contains(err.Error(), "close failed") {
+		t.Errorf("got %v, want both errors", err)
+	}
+	if strings.Join(inner.calls, " ") != "flush close" {
+		t.Errorf("got calls %v, want flush then close", inner.calls)
+	}
+}
@@ -0,0 +1,27 @@
+package rlog
+
+import "testing"
+
+func TestDiscardHandlerIsAlwaysEnabled(t *testing.T) {
+	h := NewDiscardHandler()
+
+	for _, l := range []LogLevel{LogLevelDebug, LogLevelFatal} {
+		if !h.Enabled(l) {
+			t.Errorf("got %v disabled", l)
+		}
+	}
+
+	logger := newLogger(h)
+	if n := testing.AllocsPerRun(100, func() { logger.Info("started") }); n != 0 {
+		t.Errorf("got %v allocs, want 0", n)
+	}
+}
+
+func BenchmarkDiscardHandler(b *testing.B) {
+	logger := newLogger(NewDiscardHandler())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Debug("request", "method", "GET")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package rlog
+
+import "errors"
+
+// The event log is only available on Windows, the handler is never created
+// on others.
+func NewEventLogHandler(source string, level Leveler) (LogHandler, error) {
+	return nil, errors.New("rlog: event log is only supported on windows")
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package rlog
+
+import "testing"
+
+func TestEventLogIsUnsupported(t *testing.T) {
+	if h, err := NewEventLogHandler("app", LogLevelInfo); h != nil || err == nil {
+		t.Errorf("got %v %v, want an error", h, err)
+	}
+}
@@ -0,0 +1,82 @@
+//go:build windows
+
+package rlog
+
+import (
+	"bytes"
+	"sync"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// The event id of the records written to the event log.
+const eventLogID = 1
+
+// The methods of '*eventlog.Log' used by the handler.
+type eventWriter interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+	Close() error
+}
+
+// The handler writes the records to the Windows event log, the message and
+// attributes are formatted as the payload, ex: "hello k=v".
+type eventLogHandler struct {
+	mu    sync.Mutex // Guards 'w'.
+	level Leveler
+	w     eventWriter
+}
+
+// Create a handler which writes the records with level not less than 'level'
+// to the Windows event log with 'source'. The source must be installed first,
+// ex: by 'eventlog.InstallAsEventCreate()'.
+//
+// The debug and info records are written as the information events, the warn
+// records as the warning events, and the others as the error events.
+func NewEventLogHandler(source string, level Leveler) (LogHandler, error) {
+	w, err := eventlog.Open(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventLogHandler{level: level, w: w}, nil
+}
+
+func (h *eventLogHandler) Enabled(l LogLevel) bool {
+	return l >= h.level.Level()
+}
+
+func (h *eventLogHandler) Handle(r LogRecord) {
+	buf := bytes.Buffer{}
+
+	buf.WriteString(r.Message)
+	for _, attr := range r.Attrs {
+		writeTextAttr(&buf, nil, nil, attr)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.write(r.Level, buf.String()); err != nil {
+		reportError(err)
+	}
+}
+
+func (h *eventLogHandler) write(l LogLevel, msg string) error {
+	switch {
+	case l >= LogLevelError:
+		return h.w.Error(eventLogID, msg)
+	case l >= LogLevelWarn:
+		return h.w.Warning(eventLogID, msg)
+	default:
+		return h.w.Info(eventLogID, msg)
+	}
+}
+
+func (h *eventLogHandler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.w.Close()
+}
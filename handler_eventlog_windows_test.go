@@ -0,0 +1,50 @@
+//go:build windows
+
+package rlog
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Records the events by type.
+type eventRecorder struct {
+	events []string
+}
+
+func (w *eventRecorder) Info(eid uint32, msg string) error {
+	w.events = append(w.events, "info "+msg)
+	return nil
+}
+
+func (w *eventRecorder) Warning(eid uint32, msg string) error {
+	w.events = append(w.events, "warning "+msg)
+	return nil
+}
+
+func (w *eventRecorder) Error(eid uint32, msg string) error {
+	w.events = append(w.events, "error "+msg)
+	return nil
+}
+
+func (w *eventRecorder) Close() error {
+	return nil
+}
+
+func TestEventLogTypeByLevel(t *testing.T) {
+	w := &eventRecorder{}
+	h := &eventLogHandler{level: LogLevelDebug, w: w}
+
+	for _, l := range []LogLevel{
+		LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelFatal,
+	} {
+		h.Handle(LogRecord{Level: l, Message: "m", Attrs: []LogAttr{Int("k", 1)}})
+	}
+
+	want := []string{
+		"info m k=1", "info m k=1", "warning m k=1", "error m k=1", "error m k=1",
+	}
+	if fmt.Sprint(w.events) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", w.events, want)
+	}
+}
@@ -0,0 +1,38 @@
+package rlog
+
+// The handler prepends the fixed fields to every record.
+type fieldsHandler struct {
+	inner  LogHandler
+	fields []LogAttr
+}
+
+// Create a handler which prepends 'fields' to the attributes of every record,
+// then forwards them to 'inner'. Ex: the "service", "version" and "host" which
+// belong to all the records regardless of the loggers.
+func NewWithFields(inner LogHandler, fields ...LogAttr) LogHandler {
+	return &fieldsHandler{inner: inner, fields: concatAttrs(fields, nil)}
+}
+
+func (h *fieldsHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *fieldsHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *fieldsHandler) Handle(r LogRecord) {
+	if len(h.fields) > 0 {
+		r.Attrs = concatAttrs(h.fields, r.Attrs)
+	}
+
+	h.inner.Handle(r)
+}
+
+func (h *fieldsHandler) Flush() error {
+	return flushHandler(h.inner)
+}
+
+func (h *fieldsHandler) Close() error {
+	return closeHandler(h.inner)
+}
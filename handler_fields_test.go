@@ -0,0 +1,21 @@
+package rlog
+
+import "testing"
+
+func TestWithFields(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+
+	fields := []LogAttr{Str("service", "api")}
+	logger := newLogger(NewWithFields(mem, fields...))
+
+	// The fields are copied.
+	fields[0].Value = "changed"
+
+	logger.With("k", 1).Info("a", "n", 2)
+
+	rs := snapshot()
+	if len(rs) != 1 || len(rs[0].Attrs) != 3 ||
+		rs[0].Attrs[0] != (LogAttr{Key: "service", Value: "api"}) {
+		t.Errorf("got %v, want the fields first", rs)
+	}
+}
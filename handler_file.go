@@ -0,0 +1,183 @@
+package rlog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// The writer appends to the file at 'path', and rotates it once the size
+// exceeds 'maxBytes', ex: "app.log" -> "app.log.1" -> "app.log.2".
+type rotatingWriter struct {
+	mu         sync.Mutex // Guards the writes and rotations.
+	path       string
+	maxBytes   int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+func newRotatingWriter(
+	path string,
+	maxBytes int64,
+	maxBackups int,
+) (*rotatingWriter, error) {
+	w := &rotatingWriter{
+		path:       path,
+		maxBytes:   maxBytes,
+		maxBackups: maxBackups,
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *rotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	w.f = f
+	w.size = info.Size()
+
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Never leave an empty file, a record larger than 'maxBytes' is written
+	// to a file alone.
+	var rotateErr error
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		rotateErr = w.rotate()
+	}
+
+	// The current file is still open if the rotation failed, so the record is
+	// kept and the rotation is retried by the next write.
+	n, err = w.f.Write(p)
+	w.size += int64(n)
+
+	if err == nil {
+		err = rotateErr
+	}
+
+	return n, err
+}
+
+// Rename the files while the current one is still open, and swap the file only
+// if all succeed, so a failure never leaves the writer without a file.
+func (w *rotatingWriter) rotate() error {
+	if err := w.shiftBackups(); err != nil {
+		return err
+	}
+
+	old := w.f
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	return old.Close()
+}
+
+func (w *rotatingWriter) shiftBackups() error {
+	if w.maxBackups > 0 {
+		// The oldest one is overwritten by the rename.
+		for i := w.maxBackups - 1; i > 0; i-- {
+			older := fmt.Sprintf("%s.%d", w.path, i)
+			if _, err := os.Stat(older); err == nil {
+				if err = os.Rename(older, fmt.Sprintf("%s.%d", w.path, i+1)); err != nil {
+					return err
+				}
+			}
+		}
+
+		// The current file is moved already if the last open failed.
+		if err := os.Rename(w.path, w.path+".1"); err != nil &&
+			!os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sync the current file to the disk.
+func (w *rotatingWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.f.Sync()
+}
+
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.f.Close()
+}
+
+// The handler writes the records in JSON to a file with size-based rotation.
+type rotatingFileHandler struct {
+	*jsonHandler
+	w *rotatingWriter
+}
+
+// Create a handler which appends the records with level not less than 'level'
+// to the file at 'path' in JSON, and the file is synced to the disk after
+// writing the records at or above 'LogLevelError'.
+//
+// Once the file exceeds 'maxBytes', it's renamed to "<path>.1", the older
+// backups are shifted, ex: "<path>.1" to "<path>.2", and at most 'maxBackups'
+// backups are kept. The rotation is disabled if 'maxBytes' is not positive.
+func NewRotatingFileHandler(
+	path string,
+	maxBytes int64,
+	maxBackups int,
+	level Leveler,
+) (LogHandler, error) {
+	return NewRotatingFileHandlerWithOptions(path, maxBytes, maxBackups,
+		&HandlerOptions{Level: level, SyncLevel: LogLevelError})
+}
+
+// Same as 'NewRotatingFileHandler()', with the options. A nil 'opts' is same
+// as the zero value, which never syncs the file.
+func NewRotatingFileHandlerWithOptions(
+	path string,
+	maxBytes int64,
+	maxBackups int,
+	opts *HandlerOptions,
+) (LogHandler, error) {
+	w, err := newRotatingWriter(path, maxBytes, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rotatingFileHandler{
+		jsonHandler: NewJSONHandlerWithOptions(w, opts).(*jsonHandler),
+		w:           w,
+	}, nil
+}
+
+func (h *rotatingFileHandler) Flush() error {
+	return h.w.Sync()
+}
+
+func (h *rotatingFileHandler) Close() error {
+	return h.w.Close()
+}
@@ -0,0 +1,70 @@
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingWriterSurvivesFailedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	w, err := newRotatingWriter(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// The backup can't be overwritten by a file.
+	if err := os.Mkdir(path+".1", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Write([]byte("first line\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("second\n")); err == nil {
+		t.Error("got nil error, want the rotation error")
+	}
+
+	if err := os.Remove(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatalf("write after the failed rotation: %v", err)
+	}
+
+	backup, _ := os.ReadFile(path + ".1")
+	current, _ := os.ReadFile(path)
+
+	if string(backup) != "first line\nsecond\n" || string(current) != "third\n" {
+		t.Errorf("got backup %q and current %q", backup, current)
+	}
+}
+
+func TestRotatingFileHandlerRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	h, err := NewRotatingFileHandler(path, 64, 2, LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		h.Handle(LogRecord{Level: LogLevelInfo, Message: strings.Repeat("x", 40)})
+	}
+
+	if err := closeHandler(h); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("stat %s: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("got %v for the 3rd backup, want not exist", err)
+	}
+}
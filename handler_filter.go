@@ -0,0 +1,36 @@
+package rlog
+
+// The handler forwards only the records matching the predicate.
+type filterHandler struct {
+	inner LogHandler
+	pred  func(LogRecord) bool
+}
+
+// Create a handler which forwards the records to 'inner' only if 'pred'
+// returns true, ex: to route the records of a module to a sink. Compose it with
+// the multi handler to split the records to different sinks.
+func NewFilterHandler(inner LogHandler, pred func(LogRecord) bool) LogHandler {
+	return &filterHandler{inner: inner, pred: pred}
+}
+
+func (h *filterHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *filterHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *filterHandler) Handle(r LogRecord) {
+	if h.pred(r) {
+		h.inner.Handle(r)
+	}
+}
+
+func (h *filterHandler) Flush() error {
+	return flushHandler(h.inner)
+}
+
+func (h *filterHandler) Close() error {
+	return closeHandler(h.inner)
+}
@@ -0,0 +1,25 @@
+package rlog
+
+import "testing"
+
+func TestFilterHandler(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h := NewFilterHandler(mem, func(r LogRecord) bool {
+		for _, a := range r.Attrs {
+			if a.Key == KEY_MODULE && a.Value == "db" {
+				return true
+			}
+		}
+		return false
+	})
+
+	newLogger(h).Info("dropped")
+	newLogger(h).With(KEY_MODULE, "db").Info("kept")
+
+	if rs := snapshot(); len(rs) != 1 || rs[0].Message != "kept" {
+		t.Errorf("got %v, want the db record only", rs)
+	}
+	if h.Enabled(LogLevelDebug) {
+		t.Error("got the level of inner ignored")
+	}
+}
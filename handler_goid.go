@@ -0,0 +1,66 @@
+package rlog
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// The key of the goroutine ID attached by the goroutine ID handler.
+const KEY_GOID = "goid"
+
+// The handler attaches the ID of the calling goroutine to every record.
+type goidHandler struct {
+	inner LogHandler
+}
+
+// Create a handler which attaches the ID of the goroutine calling the log
+// method with 'KEY_GOID', then forwards the records to 'inner'.
+//
+// The ID is parsed from the 'runtime.Stack()', which is not cheap. And the
+// handler must not be wrapped by the async handlers, otherwise the ID of the
+// background goroutine is attached.
+func NewGoroutineIDHandler(inner LogHandler) LogHandler {
+	return &goidHandler{inner: inner}
+}
+
+func (h *goidHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *goidHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *goidHandler) Handle(r LogRecord) {
+	r.Attrs = concatAttrs(r.Attrs, []LogAttr{{Key: KEY_GOID, Value: goid()}})
+	h.inner.Handle(r)
+}
+
+func (h *goidHandler) Flush() error {
+	return flushHandler(h.inner)
+}
+
+func (h *goidHandler) Close() error {
+	return closeHandler(h.inner)
+}
+
+// Return the ID of the calling goroutine, parsed from the first line of its
+// stack, ex: "goroutine 18 [running]:". Or 0 if failed.
+func goid() uint64 {
+	var buf [64]byte
+
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
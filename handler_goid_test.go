@@ -0,0 +1,30 @@
+package rlog
+
+import "testing"
+
+func TestGoroutineIDHandler(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	logger := newLogger(NewGoroutineIDHandler(mem))
+
+	logger.Info("a")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("b")
+	}()
+	<-done
+
+	rs := snapshot()
+	if len(rs) != 2 {
+		t.Fatalf("got %d records, want 2", len(rs))
+	}
+
+	a, b := rs[0].Attrs[0], rs[1].Attrs[0]
+	if a.Key != KEY_GOID || a.Value != goid() || a.Value == uint64(0) {
+		t.Errorf("got %v, want the ID %d", a, goid())
+	}
+	if b.Value == a.Value || b.Value == uint64(0) {
+		t.Errorf("got %v of another goroutine, want a different ID", b)
+	}
+}
@@ -0,0 +1,85 @@
+package rlog
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// The key of the content hash attached by the hash handler.
+const KEY_LOGHASH = "loghash"
+
+// The handler attaches the hash of the content to every record.
+type hashHandler struct {
+	inner LogHandler
+}
+
+// Create a handler which attaches the FNV-1a hash of the level, message and
+// attributes in hex with 'KEY_LOGHASH', then forwards the records to 'inner',
+// ex: for the pipelines deduplicating the records downstream.
+//
+// The attributes are sorted by key before hashing, so the hash is stable
+// regardless of their order. The time and source are not hashed.
+func NewHashHandler(inner LogHandler) LogHandler {
+	return &hashHandler{inner: inner}
+}
+
+func (h *hashHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *hashHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *hashHandler) Handle(r LogRecord) {
+	sum := strconv.FormatUint(contentHash(r), 16)
+	r.Attrs = concatAttrs(r.Attrs, []LogAttr{{Key: KEY_LOGHASH, Value: sum}})
+	h.inner.Handle(r)
+}
+
+func (h *hashHandler) Flush() error {
+	return flushHandler(h.inner)
+}
+
+func (h *hashHandler) Close() error {
+	return closeHandler(h.inner)
+}
+
+func contentHash(r LogRecord) uint64 {
+	hs := fnv.New64a()
+
+	fmt.Fprintf(hs, "%d\x00%s\x00", r.Level, r.Message)
+	hashAttrs(hs, r.Attrs)
+
+	return hs.Sum64()
+}
+
+// Write the attributes sorted by key, and the groups recursively.
+func hashAttrs(w hash.Hash64, attrs []LogAttr) {
+	sorted := make([]LogAttr, len(attrs))
+	for i, attr := range attrs {
+		sorted[i] = attr.Resolve()
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	for _, attr := range sorted {
+		io.WriteString(w, attr.Key)
+
+		if group, ok := attr.Value.([]LogAttr); ok {
+			io.WriteString(w, "{")
+			hashAttrs(w, group)
+			io.WriteString(w, "}")
+			continue
+		}
+
+		// The type is written too, ex: 1 and "1" differ.
+		fmt.Fprintf(w, "=%T:%+v\x00", attr.Value, attr.Value)
+	}
+}
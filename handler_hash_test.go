@@ -0,0 +1,65 @@
+package rlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHashIsStableAndDistinct(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h := NewHashHandler(mem)
+
+	record := func(l LogLevel, msg string, attrs ...LogAttr) LogRecord {
+		return LogRecord{Level: l, Message: msg, Attrs: attrs}
+	}
+	group := Group("g", "x", 1, "y", 2)
+
+	same := record(LogLevelInfo, "m", Group("g", "y", 2, "x", 1), Int("a", 1))
+	same.Time = time.Unix(1, 0)
+
+	for _, r := range []LogRecord{
+		record(LogLevelInfo, "m", Int("a", 1), group),
+		// Same content in another order and time.
+		same,
+		record(LogLevelInfo, "m", Int("a", 2), group),
+		record(LogLevelWarn, "m", Int("a", 1), group),
+		record(LogLevelInfo, "n", Int("a", 1), group),
+	} {
+		h.Handle(r)
+	}
+
+	hashes := []any{}
+	for _, r := range snapshot() {
+		last := r.Attrs[len(r.Attrs)-1]
+		if last.Key != KEY_LOGHASH {
+			t.Fatalf("got attrs %v, want %s last", r.Attrs, KEY_LOGHASH)
+		}
+		hashes = append(hashes, last.Value)
+	}
+
+	if hashes[0] != hashes[1] {
+		t.Errorf("got %v and %v, want the same hash", hashes[0], hashes[1])
+	}
+
+	seen := map[any]bool{}
+	for i, s := range hashes {
+		if i != 1 && seen[s] {
+			t.Errorf("got the duplicate hash %v in %v", s, hashes)
+		}
+		seen[s] = true
+	}
+}
+
+func TestHashDiffersByValueType(t *testing.T) {
+	for _, pair := range [][2]LogAttr{
+		{Int("k", 1), Str("k", "1")},
+		{Any("k", nil), Str("k", "<nil>")},
+	} {
+		a := LogRecord{Level: LogLevelInfo, Message: "m", Attrs: pair[:1]}
+		b := LogRecord{Level: LogLevelInfo, Message: "m", Attrs: pair[1:]}
+
+		if contentHash(a) == contentHash(b) {
+			t.Errorf("got the same hash of %v and %v", pair[0], pair[1])
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package rlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// The batching and retrying of the HTTP handler.
+const (
+	httpMaxBatch      = 100
+	httpFlushInterval = time.Second
+	httpMaxAttempts   = 3
+	httpRetryBackoff  = 100 * time.Millisecond
+
+	// The timeout of each request if the client has none.
+	httpRequestTimeout = 10 * time.Second
+)
+
+// The handler posts the batches of records to an HTTP endpoint as the JSON
+// arrays, ex:
+//
+//	[{"time":"...","level":"INFO","msg":"hello","k":"v"},{...}]
+type httpHandler struct {
+	url    string
+	client *http.Client
+	enc    *jsonHandler // Encodes the records with the options, never writes.
+
+	ctx    context.Context // Canceled once closed, to stop the retries.
+	cancel context.CancelFunc
+}
+
+// Create a handler which posts the records with level not less than 'level' to
+// 'url' with 'client', ex: a webhook of a chat app or a custom collector. The
+// 'http.DefaultClient' is used if 'client' is nil. Each request times out
+// after 'client.Timeout', or 10 seconds if it's zero.
+//
+// The records are posted in batches of 100 or every second by a background
+// goroutine, so a slow endpoint never blocks the callers, and the batch is
+// retried with backoff on the network errors and the 429 or 5xx responses, then
+// dropped with the error written to the stderr. The returned function flushes
+// the remaining records, and the retries are stopped after it returns.
+func NewHTTPHandler(
+	url string,
+	level Leveler,
+	client *http.Client,
+) (LogHandler, func() error) {
+	return NewHTTPHandlerWithOptions(url, client, &HandlerOptions{Level: level})
+}
+
+// Same as 'NewHTTPHandler()', with the options of the JSON handler, ex: the
+// 'OnError' is called with the batches failed to post instead of writing them
+// to the stderr. A nil 'opts' is same as the zero value.
+func NewHTTPHandlerWithOptions(
+	url string,
+	client *http.Client,
+	opts *HandlerOptions,
+) (LogHandler, func() error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	enc := &jsonHandler{}
+	enc.init(io.Discard, opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return NewBatchHandler(&httpHandler{
+		url:    url,
+		client: client,
+		enc:    enc,
+		ctx:    ctx,
+		cancel: cancel,
+	}, httpMaxBatch, httpFlushInterval)
+}
+
+func (h *httpHandler) Enabled(l LogLevel) bool {
+	return h.enc.Enabled(l)
+}
+
+func (h *httpHandler) CaptureSource() bool {
+	return h.enc.CaptureSource()
+}
+
+func (h *httpHandler) Handle(r LogRecord) {
+	h.BatchHandle([]LogRecord{r})
+}
+
+func (h *httpHandler) BatchHandle(rs []LogRecord) {
+	buf := bytes.Buffer{}
+
+	buf.WriteByte('[')
+	for i, r := range rs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		h.enc.writeRecord(&buf, r)
+	}
+	buf.WriteByte(']')
+
+	if err := h.post(buf.Bytes()); err != nil {
+		h.enc.opts.onError(err)
+	}
+}
+
+// Post the body, and retry on the transient failures.
+func (h *httpHandler) post(body []byte) error {
+	backoff := httpRetryBackoff
+
+	for attempt := 1; ; attempt++ {
+		retry, err := h.postOnce(body)
+		if err == nil || !retry || attempt == httpMaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-h.ctx.Done():
+			timer.Stop()
+			return err
+		}
+
+		backoff *= 2
+	}
+}
+
+// Return whether the failure is transient and worth retrying.
+func (h *httpHandler) postOnce(body []byte) (retry bool, err error) {
+	ctx := h.ctx
+	if h.client.Timeout == 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, httpRequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url,
+		bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return h.ctx.Err() == nil, err
+	}
+
+	// Drain the body, so the connection is reused.
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("rlog: post %s: %s", h.url, resp.Status)
+	retry = resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500
+
+	return retry, err
+}
+
+func (h *httpHandler) Close() error {
+	h.cancel()
+	return nil
+}
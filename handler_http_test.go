@@ -0,0 +1,108 @@
+package rlog
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPHandlerPostsBatchAndRetries(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		payload  []map[string]any
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if attempts++; attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Errorf("unmarshal %q: %v", body, err)
+			}
+		}))
+	defer srv.Close()
+
+	h, closeFn := NewHTTPHandler(srv.URL, LogLevelInfo, nil)
+	defer SnapshotRegistry()()
+	RegisterLogHandler(t.Name(), h)
+
+	logger := GetLogger(t.Name())
+	logger.Info("a", "k", 1)
+	logger.Debug("dropped")
+	logger.Warn("b")
+
+	if err := closeFn(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if attempts != 2 {
+		t.Errorf("got %d attempts, want 2", attempts)
+	}
+	if len(payload) != 2 || payload[0]["msg"] != "a" || payload[0]["k"] != 1.0 ||
+		payload[1]["msg"] != "b" || payload[1]["level"] != "WARN" {
+		t.Errorf("got payload %v", payload)
+	}
+}
+
+func TestHTTPHandlerNeverBlocksCallers(t *testing.T) {
+	captureStderr(t)
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+	defer srv.Close()
+	defer close(release)
+
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	h, closeFn := NewHTTPHandler(srv.URL, LogLevelInfo, client)
+	defer closeFn()
+
+	start := time.Now()
+	for i := 0; i < httpMaxBatch*2; i++ {
+		h.Handle(LogRecord{Level: LogLevelInfo, Message: "m"})
+	}
+
+	if d := time.Since(start); d > 40*time.Millisecond {
+		t.Errorf("handling the full batches took %v", d)
+	}
+}
+
+func TestHTTPHandlerReportsToOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+	defer srv.Close()
+
+	var errs []error
+	h, closeFn := NewHTTPHandlerWithOptions(srv.URL, nil, &HandlerOptions{
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "a"})
+	if err := closeFn(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The 4xx responses other than 429 are not retried.
+	if len(errs) != 1 {
+		t.Errorf("got errors %v, want 1", errs)
+	}
+}
@@ -0,0 +1,188 @@
+package rlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// The prefix of the values failed to be marshalled.
+const JSON_ERROR_PREFIX = "!ERROR:"
+
+// The placeholder of the values panicked when marshalled.
+const JSON_MARSHAL_PANIC = "<!marshal-error>"
+
+// The handler writes each record as one line of JSON object, ex:
+//
+//	{"time":"2023-01-02T15:04:05.999999999Z","level":"INFO","msg":"hello","k":"v"}
+//
+// The object is written field by field rather than marshalled from a map, so
+// the attributes keep the order in 'LogRecord.Attrs', and the line is stable
+// for diffing. Only the keys of the map values are sorted by 'encoding/json'.
+type jsonHandler struct {
+	baseHandler
+}
+
+// Create a handler which writes the records with level not less than 'level'
+// to 'w' in JSON. Pass a '*LevelVar' to change the level at runtime.
+func NewJSONHandler(w io.Writer, level Leveler) LogHandler {
+	return NewJSONHandlerWithOptions(w, &HandlerOptions{Level: level})
+}
+
+// Same as 'NewJSONHandler()', with the options. A nil 'opts' is same as the
+// zero value.
+func NewJSONHandlerWithOptions(w io.Writer, opts *HandlerOptions) LogHandler {
+	h := &jsonHandler{}
+	h.init(w, opts)
+
+	return h
+}
+
+func (h *jsonHandler) Handle(r LogRecord) {
+	buf := bytes.Buffer{}
+
+	h.writeRecord(&buf, r)
+	buf.WriteByte('\n')
+
+	h.write(r.Level, buf.Bytes())
+}
+
+// Write the record as a JSON object without the trailing newline.
+func (h *jsonHandler) writeRecord(buf *bytes.Buffer, r LogRecord) {
+	buf.WriteByte('{')
+	if !r.Time.IsZero() {
+		h.writeTime(buf, r.Time)
+	}
+	writeJSONField(buf, KEY_LEVEL, r.Level.String())
+	if r.Source != nil {
+		writeJSONField(buf, KEY_SOURCE, r.Source)
+	}
+	writeJSONField(buf, KEY_MESSAGE, r.Message)
+
+	for _, attr := range h.attrsOf(r) {
+		writeJSONAttr(buf, &h.opts, nil, attr)
+	}
+
+	buf.WriteByte('}')
+}
+
+func (h *jsonHandler) writeTime(buf *bytes.Buffer, t time.Time) {
+	t = h.opts.timeValue(t)
+	key := h.opts.TimeKey
+	if key == "" {
+		key = KEY_TIME
+	}
+
+	switch h.opts.TimeFormat {
+	case TIME_FORMAT_EPOCH_MILLIS:
+		writeJSONField(buf, key, t.UnixMilli())
+	case "":
+		writeJSONField(buf, key, t.Format(time.RFC3339Nano))
+	default:
+		writeJSONField(buf, key, t.Format(h.opts.TimeFormat))
+	}
+}
+
+// Write the attribute, and the group is written as a nested object. The
+// 'groups' are the names of the groups containing the attribute.
+func writeJSONAttr(
+	buf *bytes.Buffer,
+	opts *HandlerOptions,
+	groups []string,
+	attr LogAttr,
+) {
+	attr = attr.Resolve()
+
+	group, ok := attr.Value.([]LogAttr)
+	if !ok {
+		if attr = opts.replaceAttr(groups, attr); attr.Key == "" {
+			return
+		}
+
+		if opts.omitted(attr.Value) {
+			return
+		}
+
+		if err, ok := attr.Value.(error); ok {
+			for _, a := range errorAttrs(attr.Key, err) {
+				writeJSONField(buf, a.Key, a.Value)
+			}
+			return
+		}
+
+		switch v := attr.Value.(type) {
+		case time.Duration:
+			attr.Value = opts.durationValue(v)
+		case time.Time:
+			attr.Value = opts.timeValue(v)
+		default:
+			attr.Value = opts.sliceValue(v)
+		}
+
+		writeJSONField(buf, attr.Key, attr.Value)
+		return
+	}
+
+	// The group with empty key is inlined.
+	if attr.Key != "" {
+		writeJSONSeparator(buf)
+		writeJSONValue(buf, attr.Key)
+		buf.WriteString(":{")
+		groups = appendGroup(groups, attr.Key)
+	}
+
+	for _, a := range group {
+		writeJSONAttr(buf, opts, groups, a)
+	}
+
+	if attr.Key != "" {
+		buf.WriteByte('}')
+	}
+}
+
+func writeJSONField(buf *bytes.Buffer, key string, value any) {
+	writeJSONSeparator(buf)
+	writeJSONValue(buf, key)
+	buf.WriteByte(':')
+	writeJSONValue(buf, value)
+}
+
+// Write the comma unless it's the first field of an object.
+func writeJSONSeparator(buf *bytes.Buffer) {
+	if b := buf.Bytes(); len(b) > 0 && b[len(b)-1] != '{' {
+		buf.WriteByte(',')
+	}
+}
+
+// Write the value with its JSON type kept, ex: the numbers and booleans are
+// never quoted, the time is written in RFC3339, and the '[]byte' is written in
+// base64.
+//
+// The value failed to be marshalled is written as a string of the error with
+// 'JSON_ERROR_PREFIX', ex: "!ERROR:json: unsupported value: NaN". And the
+// value panicked, ex: by its 'MarshalJSON()', is written as
+// 'JSON_MARSHAL_PANIC', so the rest of the record is kept.
+func writeJSONValue(buf *bytes.Buffer, value any) {
+	if t, ok := value.(time.Time); ok {
+		value = t.Format(time.RFC3339Nano)
+	}
+
+	bs, err := marshalJSON(value)
+	if err != nil {
+		bs, _ = json.Marshal(JSON_ERROR_PREFIX + err.Error())
+	}
+
+	buf.Write(bs)
+}
+
+func marshalJSON(value any) (bs []byte, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			// Not marshalled, which escapes the '<' and '>'.
+			bs, err = []byte(`"`+JSON_MARSHAL_PANIC+`"`), nil
+		}
+	}()
+
+	return json.Marshal(value)
+}
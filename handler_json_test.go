@@ -0,0 +1,270 @@
+package rlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Write the record with the JSON handler, and return the line.
+func writeJSON(opts *HandlerOptions, r LogRecord) string {
+	buf := bytes.Buffer{}
+	NewJSONHandlerWithOptions(&buf, opts).Handle(r)
+
+	return buf.String()
+}
+
+func TestJSONHandler(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	got := writeJSON(nil, LogRecord{
+		Time:    at,
+		Level:   LogLevelWarn,
+		Message: `say "hi"`,
+		Attrs: []LogAttr{
+			Str("k", "v\n"),
+			Group("g", "n", 1),
+		},
+	})
+
+	want := `{"time":"2024-01-02T03:04:05Z","level":"WARN","msg":"say \"hi\"",` +
+		`"k":"v\n","g":{"n":1}}` + "\n"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if !json.Valid([]byte(got)) {
+		t.Errorf("got invalid JSON %s", got)
+	}
+}
+
+func TestJSONHandlerLevel(t *testing.T) {
+	h := NewJSONHandler(&bytes.Buffer{}, LogLevelWarn)
+
+	if h.Enabled(LogLevelInfo) || !h.Enabled(LogLevelError) {
+		t.Error("got the wrong enablement")
+	}
+}
+
+func TestJSONHandlerDedupKeys(t *testing.T) {
+	attrs := []LogAttr{
+		Str("k", "bound"),
+		Group("g", "a", 1, "a", 2),
+		Int("n", 1),
+		Str("k", "call"),
+	}
+
+	got := writeJSON(&HandlerOptions{DedupKeys: true},
+		LogRecord{Level: LogLevelInfo, Message: "m", Attrs: attrs})
+
+	want := `{"level":"INFO","msg":"m","k":"call","g":{"a":2},"n":1}` + "\n"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// Kept by default.
+	got = writeJSON(nil, LogRecord{Level: LogLevelInfo, Message: "m", Attrs: attrs})
+	want = `{"level":"INFO","msg":"m","k":"bound","g":{"a":1,"a":2},"n":1,` +
+		`"k":"call"}` + "\n"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestReplaceAttr(t *testing.T) {
+	var seen [][]string
+	opts := &HandlerOptions{
+		ReplaceAttr: func(groups []string, a LogAttr) LogAttr {
+			seen = append(seen, groups)
+
+			switch a.Key {
+			case "password":
+				a.Value = "***"
+			case "drop":
+				a.Key = ""
+			case "old":
+				a.Key = "new"
+			}
+			return a
+		},
+	}
+
+	got := writeJSON(opts, LogRecord{
+		Level:   LogLevelInfo,
+		Message: "m",
+		Attrs: []LogAttr{
+			Group("user", "password", "secret", "drop", 1),
+			Str("old", "v"),
+		},
+	})
+
+	want := `{"level":"INFO","msg":"m","user":{"password":"***"},"new":"v"}` + "\n"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if len(seen) != 3 || len(seen[0]) != 1 || seen[0][0] != "user" || seen[2] != nil {
+		t.Errorf("got groups %v", seen)
+	}
+}
+
+func TestJSONHandlerKeepsValueTypes(t *testing.T) {
+	got := writeJSON(nil, LogRecord{
+		Level:   LogLevelInfo,
+		Message: "m",
+		Attrs: []LogAttr{
+			Int("i", -1),
+			Any("u", uint64(1<<63)),
+			Any("f", 1.5),
+			Bool("b", true),
+			Any("nil", nil),
+			Any("n", json.Number("12")),
+			Str("s", "1"),
+		},
+	})
+
+	want := `{"level":"INFO","msg":"m","i":-1,"u":9223372036854775808,"f":1.5,` +
+		`"b":true,"nil":null,"n":12,"s":"1"}` + "\n"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestDurationFormat(t *testing.T) {
+	r := LogRecord{
+		Level:   LogLevelInfo,
+		Message: "m",
+		Attrs:   []LogAttr{Dur("took", 1500*time.Millisecond)},
+	}
+
+	if got := writeJSON(nil, r); got != `{"level":"INFO","msg":"m","took":"1.5s"}`+"\n" {
+		t.Errorf("got %s", got)
+	}
+
+	got := writeJSON(&HandlerOptions{DurationFormat: DurationAsMillis}, r)
+	if got != `{"level":"INFO","msg":"m","took":1500}`+"\n" {
+		t.Errorf("got %s", got)
+	}
+
+	buf := bytes.Buffer{}
+	NewTextHandler(&buf, LogLevelInfo).Handle(r)
+	if buf.String() != "INFO m took=1.5s\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestJSONTimeKeyAndFormat(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC)
+	r := LogRecord{Time: at, Level: LogLevelInfo, Message: "m"}
+
+	for _, tc := range []struct {
+		opts *HandlerOptions
+		want string
+	}{
+		{nil, `{"time":"2024-01-02T03:04:05.006Z",`},
+		{&HandlerOptions{TimeKey: "@timestamp", TimeFormat: time.RFC3339},
+			`{"@timestamp":"2024-01-02T03:04:05Z",`},
+		{&HandlerOptions{TimeFormat: TIME_FORMAT_EPOCH_MILLIS},
+			`{"time":1704164645006,`},
+	} {
+		if got := writeJSON(tc.opts, r); got != tc.want+`"level":"INFO","msg":"m"}`+"\n" {
+			t.Errorf("got %s, want %s", got, tc.want)
+		}
+	}
+
+	// The zero time is omitted.
+	if got := writeJSON(nil, LogRecord{Level: LogLevelInfo, Message: "m"}); got != `{"level":"INFO","msg":"m"}`+"\n" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestJSONOmitEmpty(t *testing.T) {
+	r := LogRecord{
+		Message: "m",
+		Attrs: []LogAttr{
+			Str("empty", ""),
+			Any("nil", nil),
+			Any("zero", time.Time{}),
+			Int("n", 0),
+			Group("g", "s", "", "k", "v"),
+		},
+	}
+
+	got := writeJSON(&HandlerOptions{OmitEmpty: true}, r)
+	if want := `"msg":"m","n":0,"g":{"k":"v"}}` + "\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("got %s, want suffix %s", got, want)
+	}
+
+	got = writeJSON(nil, r)
+	want := `"msg":"m","empty":"","nil":null,"zero":"0001-01-01T00:00:00Z",` +
+		`"n":0,"g":{"s":"","k":"v"}}` + "\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("got %s, want suffix %s", got, want)
+	}
+}
+
+func TestJSONAttrsKeepInsertionOrder(t *testing.T) {
+	keys := []string{"zeta", "alpha", `q"uote`, "mid", "b\tab", "0"}
+
+	attrs := make([]LogAttr, 0, len(keys)+1)
+	for i, k := range keys {
+		attrs = append(attrs, Int(k, i))
+	}
+	attrs = append(attrs, Group("g", "y", `"v"`, "x", "<&>"))
+
+	// Same for each run, unlike the map ordering.
+	for i := 0; i < 10; i++ {
+		got := writeJSON(nil, LogRecord{Message: "m", Attrs: attrs})
+
+		want := `"msg":"m","zeta":0,"alpha":1,"q\"uote":2,"mid":3,"b\tab":4,"0":5,` +
+			`"g":{"y":"\"v\"","x":"\u003c\u0026\u003e"}}` + "\n"
+		if !strings.HasSuffix(got, want) {
+			t.Fatalf("got %s, want suffix %s", got, want)
+		}
+		if !json.Valid([]byte(got)) {
+			t.Fatalf("got invalid JSON %s", got)
+		}
+	}
+}
+
+func TestJSONMaxSliceLen(t *testing.T) {
+	opts := &HandlerOptions{MaxSliceLen: 3}
+
+	got := writeJSON(opts, LogRecord{
+		Message: "m",
+		Attrs: []LogAttr{
+			Any("long", []int{1, 2, 3, 4, 5}),
+			Any("short", []string{"a", "b"}),
+			Any("arr", [4]int{1, 2, 3, 4}),
+			Any("bytes", []byte("abcdef")),
+		},
+	})
+
+	want := `"long":[1,2,3,"…(+2 more)"],"short":["a","b"],"arr":[1,2,3,"…(+1 more)"],` +
+		`"bytes":"YWJjZGVm"}` + "\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("got %s, want suffix %s", got, want)
+	}
+}
+
+type panicMarshaler struct{}
+
+func (panicMarshaler) MarshalJSON() ([]byte, error) {
+	panic("boom")
+}
+
+func TestJSONMarshalPanicKeepsTheRecord(t *testing.T) {
+	got := writeJSON(nil, LogRecord{
+		Message: "m",
+		Attrs:   []LogAttr{Any("bad", panicMarshaler{}), Int("n", 1)},
+	})
+
+	want := `"msg":"m","bad":"` + JSON_MARSHAL_PANIC + `","n":1}` + "\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("got %s, want suffix %s", got, want)
+	}
+	if !json.Valid([]byte(got)) {
+		t.Errorf("got invalid JSON %s", got)
+	}
+}
@@ -0,0 +1,57 @@
+package rlog
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+)
+
+// The handler writes each record as one line of logfmt, ex:
+//
+//	time=2023-01-02T15:04:05.999999999Z level=INFO msg=hello k=v s="with space"
+//
+// Unlike the text handler, all the fields are written as key/value pairs, so
+// the lines are always parseable by the logfmt parsers.
+type logfmtHandler struct {
+	baseHandler
+}
+
+// Create a handler which writes the records with level not less than 'level'
+// to 'w' in logfmt. Pass a '*LevelVar' to change the level at runtime.
+func NewLogfmtHandler(w io.Writer, level Leveler) LogHandler {
+	return NewLogfmtHandlerWithOptions(w, &HandlerOptions{Level: level})
+}
+
+// Same as 'NewLogfmtHandler()', with the options. A nil 'opts' is same as the
+// zero value.
+func NewLogfmtHandlerWithOptions(w io.Writer, opts *HandlerOptions) LogHandler {
+	h := &logfmtHandler{}
+	h.init(w, opts)
+
+	return h
+}
+
+func (h *logfmtHandler) Handle(r LogRecord) {
+	buf := bytes.Buffer{}
+
+	if !r.Time.IsZero() {
+		t := h.opts.timeValue(r.Time)
+		writeTextField(&buf, nil, KEY_TIME, t.Format(time.RFC3339Nano))
+	}
+	writeTextField(&buf, nil, KEY_LEVEL, r.Level.String())
+	if r.Source != nil {
+		source := r.Source.File + ":" + strconv.Itoa(r.Source.Line)
+		writeTextField(&buf, nil, KEY_SOURCE, source)
+	}
+	writeTextField(&buf, nil, KEY_MESSAGE, r.Message)
+
+	for _, attr := range h.attrsOf(r) {
+		writeTextAttr(&buf, &h.opts, nil, attr)
+	}
+
+	buf.WriteByte('\n')
+
+	// Every field is written with a leading space.
+	h.write(r.Level, buf.Bytes()[1:])
+}
@@ -0,0 +1,79 @@
+package rlog
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogfmtHandler(t *testing.T) {
+	buf := bytes.Buffer{}
+	h := NewLogfmtHandler(&buf, LogLevelInfo)
+
+	h.Handle(LogRecord{
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   LogLevelInfo,
+		Message: "hello world",
+		Source:  &LogSource{File: "/app/main.go", Line: 42},
+		Attrs: []LogAttr{
+			Str("a b", `say "hi"`),
+			Str("empty", ""),
+			Any("raw", []byte("hi")),
+			Group("g", "k", "v"),
+		},
+	})
+
+	want := `time=2024-01-02T03:04:05Z level=INFO source=/app/main.go:42 ` +
+		`msg="hello world" a_b="say \"hi\"" empty="" raw="aGk=" g.k=v` + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	if h.Enabled(LogLevelDebug) {
+		t.Error("got debug enabled")
+	}
+}
+
+func TestLogfmtQuoting(t *testing.T) {
+	for _, tc := range []struct {
+		attr LogAttr
+		want string
+	}{
+		{Str("k", "a b"), `k="a b"`},
+		{Str("k", "a=b"), `k="a=b"`},
+		{Str("k", `a"b`), `k="a\"b"`},
+		{Str("k", "a\nb"), `k="a\nb"`},
+		{Str("k", "\t"), `k="\t"`},
+		{Str("k", ""), `k=""`},
+		{Str("k", "héllo"), `k=héllo`},
+		{Any("k", nil), `k=<nil>`},
+		{Int("a b", 1), `a_b=1`},
+		{Int("a=b", 1), `a_b=1`},
+		{Int(`a"b`, 1), `a_b=1`},
+		{Int("a\nb", 1), `a_b=1`},
+		// The attribute without key is dropped.
+		{Int("", 1), ``},
+	} {
+		buf := bytes.Buffer{}
+		NewLogfmtHandler(&buf, LogLevelInfo).Handle(LogRecord{
+			Level:   LogLevelInfo,
+			Message: "m",
+			Attrs:   []LogAttr{tc.attr},
+		})
+
+		want := strings.TrimSuffix("level=INFO msg=m "+tc.want, " ") + "\n"
+		if buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+			continue
+		}
+
+		// The quoted values are parsed back to the logged ones.
+		s, ok := tc.attr.Value.(string)
+		_, value, _ := strings.Cut(tc.want, "=")
+		if unquoted, err := strconv.Unquote(value); ok && err == nil && unquoted != s {
+			t.Errorf("got %q parsed back, want %q", unquoted, s)
+		}
+	}
+}
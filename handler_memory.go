@@ -0,0 +1,43 @@
+package rlog
+
+import "sync"
+
+// The handler keeps all the handled records in memory, ex: to assert the
+// records in unit tests.
+type memoryHandler struct {
+	level Leveler
+
+	mu      sync.Mutex // Guards 'records'.
+	records []LogRecord
+}
+
+// Create a handler which keeps the records with level not less than 'level',
+// and the returned function returns a copy of the kept records in order.
+func NewMemoryHandler(level Leveler) (LogHandler, func() []LogRecord) {
+	h := &memoryHandler{level: level}
+	return h, h.snapshot
+}
+
+func (h *memoryHandler) Enabled(l LogLevel) bool {
+	return l >= h.level.Level()
+}
+
+func (h *memoryHandler) Handle(r LogRecord) {
+	// The attributes are reused once this returns.
+	r = r.Clone()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records = append(h.records, r)
+}
+
+func (h *memoryHandler) snapshot() []LogRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	records := make([]LogRecord, len(h.records))
+	copy(records, h.records)
+
+	return records
+}
@@ -0,0 +1,51 @@
+package rlog
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMemoryHandlerKeepsRecordsInOrder(t *testing.T) {
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+
+	attrs := []LogAttr{Int("i", 0)}
+	for i := 0; i < 3; i++ {
+		attrs[0].Value = i
+		h.Handle(LogRecord{Level: LogLevelInfo, Attrs: attrs})
+	}
+
+	rs := snapshot()
+	for i, r := range rs {
+		if r.Attrs[0].Value != i {
+			t.Errorf("got record %d %v", i, r)
+		}
+	}
+
+	// The snapshot is a copy.
+	rs[0].Message = "changed"
+	if snapshot()[0].Message == "changed" {
+		t.Error("got the snapshot shared")
+	}
+}
+
+func TestMemoryHandlerIsConcurrencySafe(t *testing.T) {
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+	logger := newLogger(h)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				logger.Info("m", "j", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(snapshot()); n != 800 {
+		t.Errorf("got %d records, want 800", n)
+	}
+}
@@ -0,0 +1,79 @@
+package rlog
+
+// The handler applies the level threshold by the module of the records.
+type moduleLevelHandler struct {
+	inner    LogHandler
+	levels   map[string]LogLevel
+	fallback LogLevel
+	min      LogLevel // The lowest threshold, for 'Enabled()'.
+}
+
+// Create a handler which forwards the records to 'inner' only if the level is
+// not less than the threshold of the module in 'KEY_MODULE', ex:
+//
+//	NewModuleLevelHandler(inner, map[string]LogLevel{"db": LogLevelDebug}, LogLevelInfo)
+//
+// forwards the debug records of the module "db" and the info records of the
+// others. The records without module use 'fallback'. The 'levels' is copied.
+func NewModuleLevelHandler(
+	inner LogHandler,
+	levels map[string]LogLevel,
+	fallback LogLevel,
+) LogHandler {
+	h := &moduleLevelHandler{
+		inner:    inner,
+		levels:   make(map[string]LogLevel, len(levels)),
+		fallback: fallback,
+		min:      fallback,
+	}
+
+	for module, level := range levels {
+		h.levels[module] = level
+		if level < h.min {
+			h.min = level
+		}
+	}
+
+	return h
+}
+
+// The module is unknown before the record is created, so the level is enabled
+// if any module may accept it.
+func (h *moduleLevelHandler) Enabled(l LogLevel) bool {
+	return l >= h.min && h.inner.Enabled(l)
+}
+
+func (h *moduleLevelHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *moduleLevelHandler) Handle(r LogRecord) {
+	if r.Level >= h.threshold(r) {
+		h.inner.Handle(r)
+	}
+}
+
+func (h *moduleLevelHandler) threshold(r LogRecord) LogLevel {
+	for _, attr := range r.Attrs {
+		if attr.Key != KEY_MODULE {
+			continue
+		}
+
+		if module, ok := attr.Value.(string); ok {
+			if level, ok := h.levels[module]; ok {
+				return level
+			}
+		}
+		break
+	}
+
+	return h.fallback
+}
+
+func (h *moduleLevelHandler) Flush() error {
+	return flushHandler(h.inner)
+}
+
+func (h *moduleLevelHandler) Close() error {
+	return closeHandler(h.inner)
+}
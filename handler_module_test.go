@@ -0,0 +1,26 @@
+package rlog
+
+import "testing"
+
+func TestModuleLevelHandler(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	mem, snapshot := NewMemoryHandler(LogLevelDebug)
+	levels := map[string]LogLevel{"db": LogLevelDebug, "http": LogLevelError}
+	RegisterLogHandler(t.Name(), NewModuleLevelHandler(mem, levels, LogLevelInfo))
+
+	// The levels are copied.
+	levels["db"] = LogLevelFatal
+
+	GetLoggerWithModule(t.Name(), "db").Debug("db debug")
+	GetLoggerWithModule(t.Name(), "http").Warn("dropped")
+	GetLoggerWithModule(t.Name(), "http").Error("http error")
+	GetLoggerWithModule(t.Name(), "cache").Debug("dropped")
+	GetLogger(t.Name()).Info("plain info")
+
+	rs := snapshot()
+	if len(rs) != 3 || rs[0].Message != "db debug" || rs[1].Message != "http error" ||
+		rs[2].Message != "plain info" {
+		t.Errorf("got %v", rs)
+	}
+}
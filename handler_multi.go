@@ -0,0 +1,84 @@
+package rlog
+
+// The handler fans out each record to all the enabled children.
+type multiHandler struct {
+	handlers []LogHandler
+}
+
+// Create a handler which forwards each record to all of 'handlers', ex: to
+// write the records to both console and file.
+func NewMultiHandler(handlers ...LogHandler) LogHandler {
+	hs := make([]LogHandler, 0, len(handlers))
+
+	for _, h := range handlers {
+		if h != nil {
+			hs = append(hs, h)
+		}
+	}
+
+	return &multiHandler{handlers: hs}
+}
+
+func (h *multiHandler) Enabled(l LogLevel) bool {
+	for _, c := range h.handlers {
+		if c.Enabled(l) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *multiHandler) CaptureSource() bool {
+	for _, c := range h.handlers {
+		if captureSource(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *multiHandler) Handle(r LogRecord) {
+	for _, c := range h.handlers {
+		if c.Enabled(r.Level) {
+			handleSafely(c, r)
+		}
+	}
+}
+
+// A panic in one child must not prevent the others from receiving the record,
+// and it's written to the stderr same as the panics recovered by the loggers.
+func handleSafely(h LogHandler, r LogRecord) {
+	defer func() {
+		if err := recover(); err != nil {
+			reportPanic(err)
+		}
+	}()
+
+	h.Handle(r)
+}
+
+func (h *multiHandler) Flush() error {
+	var errs multiError
+
+	for _, c := range h.handlers {
+		if err := flushHandler(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs.orNil()
+}
+
+func (h *multiHandler) Close() error {
+	var errs multiError
+
+	for _, c := range h.handlers {
+		if err := closeHandler(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs.orNil()
+}
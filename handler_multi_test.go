@@ -0,0 +1,44 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+)
+
+// The handler panics on every record.
+type panicHandler struct{}
+
+func (panicHandler) Enabled(LogLevel) bool { return true }
+
+func (panicHandler) Handle(LogRecord) { panic("boom") }
+
+func TestMultiHandlerFansOut(t *testing.T) {
+	buf := captureStderr(t)
+
+	info, infos := NewMemoryHandler(LogLevelInfo)
+	errs, errors := NewMemoryHandler(LogLevelError)
+	h := NewMultiHandler(panicHandler{}, info, nil, errs)
+
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "a"})
+	h.Handle(LogRecord{Level: LogLevelError, Message: "b"})
+
+	if rs := infos(); len(rs) != 2 {
+		t.Errorf("got %d info records, want 2", len(rs))
+	}
+	if rs := errors(); len(rs) != 1 || rs[0].Message != "b" {
+		t.Errorf("got error records %v, want b", rs)
+	}
+	if n := strings.Count(buf.String(), "rlog: handler panicked: boom"); n != 2 {
+		t.Errorf("got stderr %q, want 2 panics", buf.String())
+	}
+}
+
+func TestMultiHandlerEnabled(t *testing.T) {
+	warn, _ := NewMemoryHandler(LogLevelWarn)
+	errs, _ := NewMemoryHandler(LogLevelError)
+	h := NewMultiHandler(warn, errs)
+
+	if h.Enabled(LogLevelInfo) || !h.Enabled(LogLevelWarn) {
+		t.Error("got the wrong enablement")
+	}
+}
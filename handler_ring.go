@@ -0,0 +1,63 @@
+package rlog
+
+import "sync"
+
+// The handler keeps the most recent records in a circular buffer, ex: to dump
+// them on panic for the post-mortem debugging.
+type ringHandler struct {
+	level Leveler
+
+	mu      sync.Mutex // Guards the fields below.
+	records []LogRecord
+	next    int // Where the next record is kept.
+	full    bool
+}
+
+// Create a handler which keeps the most recent 'size' records with level not
+// less than 'level', and the returned function returns a copy of the kept
+// records, the oldest first.
+func NewRingHandler(size int, level Leveler) (LogHandler, func() []LogRecord) {
+	if size < 1 {
+		size = 1
+	}
+
+	h := &ringHandler{level: level, records: make([]LogRecord, size)}
+	return h, h.snapshot
+}
+
+func (h *ringHandler) Enabled(l LogLevel) bool {
+	return l >= h.level.Level()
+}
+
+func (h *ringHandler) Handle(r LogRecord) {
+	// The attributes are reused once this returns.
+	r = r.Clone()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records[h.next] = r
+	h.next = (h.next + 1) % len(h.records)
+
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+func (h *ringHandler) snapshot() []LogRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		records := make([]LogRecord, h.next)
+		copy(records, h.records[:h.next])
+
+		return records
+	}
+
+	records := make([]LogRecord, 0, len(h.records))
+	records = append(records, h.records[h.next:]...)
+	records = append(records, h.records[:h.next]...)
+
+	return records
+}
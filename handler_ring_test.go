@@ -0,0 +1,31 @@
+package rlog
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRingHandlerKeepsTheMostRecent(t *testing.T) {
+	h, snapshot := NewRingHandler(3, LogLevelInfo)
+	logger := newLogger(h)
+
+	logger.Info("0")
+	logger.Info("1")
+	if rs := snapshot(); len(rs) != 2 || rs[0].Message != "0" {
+		t.Errorf("got %v before full, want 0 and 1", rs)
+	}
+
+	for i := 2; i < 7; i++ {
+		logger.Info(fmt.Sprint(i))
+	}
+
+	rs := snapshot()
+	if len(rs) != 3 {
+		t.Fatalf("got %d records, want 3", len(rs))
+	}
+	for i, r := range rs {
+		if want := fmt.Sprint(i + 4); r.Message != want {
+			t.Errorf("got %q at %d, want %q", r.Message, i, want)
+		}
+	}
+}
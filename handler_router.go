@@ -0,0 +1,138 @@
+package rlog
+
+import (
+	"io"
+	"reflect"
+)
+
+// The handler dispatches each record to the handler routed for its level.
+type levelRouterHandler struct {
+	routes map[LogLevel]LogHandler
+
+	// The records with level not less than 'min' and not in 'routes' are
+	// dispatched to 'above' if set.
+	min   LogLevel
+	above LogHandler
+
+	fallback LogHandler
+}
+
+// Create a handler which dispatches each record to the handler in 'routes' for
+// its level, or 'fallback' if absent, ex: the info records to stdout and the
+// error records to stderr. The records are dropped if 'fallback' is nil.
+func NewLevelRouterHandler(
+	routes map[LogLevel]LogHandler,
+	fallback LogHandler,
+) LogHandler {
+	rs := make(map[LogLevel]LogHandler, len(routes))
+
+	for l, h := range routes {
+		if h != nil {
+			rs[l] = h
+		}
+	}
+
+	return &levelRouterHandler{routes: rs, fallback: fallback}
+}
+
+// Create a handler which writes the debug and info records to 'out' in text
+// for the humans, and the warn, error and fatal records to 'errOut' in JSON,
+// ex: for the console and a collector. The custom levels above warn are
+// written in JSON too. The records with level less than 'level' are dropped.
+func NewConsoleJSONErrorsHandler(
+	out, errOut io.Writer,
+	level Leveler,
+) LogHandler {
+	return &levelRouterHandler{
+		min:      LogLevelWarn,
+		above:    NewJSONHandler(errOut, level),
+		fallback: NewTextHandler(out, level),
+	}
+}
+
+func (h *levelRouterHandler) route(l LogLevel) LogHandler {
+	if r, ok := h.routes[l]; ok {
+		return r
+	}
+
+	if h.above != nil && l >= h.min {
+		return h.above
+	}
+
+	return h.fallback
+}
+
+func (h *levelRouterHandler) Enabled(l LogLevel) bool {
+	r := h.route(l)
+	return r != nil && r.Enabled(l)
+}
+
+func (h *levelRouterHandler) CaptureSource() bool {
+	for _, r := range h.handlers() {
+		if captureSource(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *levelRouterHandler) Handle(r LogRecord) {
+	if c := h.route(r.Level); c != nil {
+		c.Handle(r)
+	}
+}
+
+// Return the distinct routed handlers and the fallback.
+func (h *levelRouterHandler) handlers() []LogHandler {
+	hs := make([]LogHandler, 0, len(h.routes)+2)
+	seen := make(map[LogHandler]bool, len(h.routes)+2)
+
+	add := func(c LogHandler) {
+		if c == nil {
+			return
+		}
+
+		// The uncomparable handlers panic as the map keys.
+		if reflect.TypeOf(c).Comparable() {
+			if seen[c] {
+				return
+			}
+			seen[c] = true
+		}
+
+		hs = append(hs, c)
+	}
+
+	for _, c := range h.routes {
+		add(c)
+	}
+	add(h.above)
+	add(h.fallback)
+
+	return hs
+}
+
+func (h *levelRouterHandler) Flush() error {
+	var errs multiError
+
+	for _, c := range h.handlers() {
+		if err := flushHandler(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs.orNil()
+}
+
+func (h *levelRouterHandler) Close() error {
+	var errs multiError
+
+	for _, c := range h.handlers() {
+		if err := closeHandler(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs.orNil()
+}
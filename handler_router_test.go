@@ -0,0 +1,85 @@
+package rlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLevelRouterHandler(t *testing.T) {
+	errs, errors := NewMemoryHandler(LogLevelDebug)
+	rest, others := NewMemoryHandler(LogLevelInfo)
+	h := NewLevelRouterHandler(map[LogLevel]LogHandler{
+		LogLevelError: errs,
+		LogLevelWarn:  nil,
+	}, rest)
+
+	logger := newLogger(h)
+	logger.Debug("dropped")
+	logger.Info("a")
+	logger.Warn("b")
+	logger.Error("c")
+
+	if rs := errors(); len(rs) != 1 || rs[0].Message != "c" {
+		t.Errorf("got error records %v, want c", rs)
+	}
+	if rs := others(); len(rs) != 2 || rs[0].Message != "a" || rs[1].Message != "b" {
+		t.Errorf("got other records %v, want a and b", rs)
+	}
+}
+
+func TestLevelRouterHandlerWithoutFallback(t *testing.T) {
+	errs, _ := NewMemoryHandler(LogLevelDebug)
+	h := NewLevelRouterHandler(map[LogLevel]LogHandler{LogLevelError: errs}, nil)
+
+	if h.Enabled(LogLevelInfo) || !h.Enabled(LogLevelError) {
+		t.Error("got the wrong enablement")
+	}
+	h.Handle(LogRecord{Level: LogLevelInfo})
+}
+
+func TestLevelRouterHandlerClosesEachHandlerOnce(t *testing.T) {
+	shared := &lifecycleHandler{}
+	h := NewLevelRouterHandler(map[LogLevel]LogHandler{
+		LogLevelWarn:  shared,
+		LogLevelError: shared,
+	}, shared)
+
+	if err := closeHandler(h); err != nil {
+		t.Fatal(err)
+	}
+	if len(shared.calls) != 1 {
+		t.Errorf("got calls %v, want close once", shared.calls)
+	}
+}
+
+func TestConsoleJSONErrorsHandler(t *testing.T) {
+	out, errOut := bytes.Buffer{}, bytes.Buffer{}
+	logger := newLogger(NewConsoleJSONErrorsHandler(&out, &errOut, LogLevelInfo))
+
+	logger.Debug("dropped")
+	logger.Info("started", "k", 1)
+	logger.Warn("slow")
+	logger.Error("failed", "k", 2)
+	logger.Log(LogLevelFatal+1, "custom")
+
+	if got := out.String(); strings.Count(got, "\n") != 1 ||
+		!strings.HasSuffix(got, " INFO started k=1\n") {
+		t.Errorf("got text %q, want started only", got)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(errOut.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got JSON %q, want 3 lines", errOut.String())
+	}
+	for i, want := range []string{
+		`"level":"WARN","msg":"slow"}`,
+		`"level":"ERROR","msg":"failed","k":2}`,
+		`"msg":"custom"}`,
+	} {
+		if !json.Valid([]byte(lines[i])) || !strings.HasSuffix(lines[i], want) {
+			t.Errorf("got %s, want suffix %s", lines[i], want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package rlog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// The message of the record reporting the records dropped by sampling.
+const MSG_SAMPLING_DROPPED = "rlog: records dropped by sampling"
+
+type samplingCounter struct {
+	start   time.Time // The beginning of the current second.
+	passed  int
+	dropped int
+}
+
+// The handler passes at most 'perSecond' records per level to the inner handler
+// each second, the rest are dropped.
+type samplingHandler struct {
+	inner     LogHandler
+	perSecond int
+	now       func() time.Time // Replaceable in tests.
+
+	mu       sync.Mutex // Guards 'counters'.
+	counters map[LogLevel]*samplingCounter
+}
+
+// Create a handler which caps the records of each level passed to 'inner' to
+// 'perSecond' per second.
+//
+// The count of the records dropped in a second is reported by a record with
+// 'MSG_SAMPLING_DROPPED' at the same level, once the next second begins, or
+// the handler is flushed or closed.
+func NewSamplingHandler(inner LogHandler, perSecond int) LogHandler {
+	return &samplingHandler{
+		inner:     inner,
+		perSecond: perSecond,
+		now:       time.Now,
+		counters:  make(map[LogLevel]*samplingCounter),
+	}
+}
+
+func (h *samplingHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *samplingHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *samplingHandler) Handle(r LogRecord) {
+	now := h.now()
+	pass, dropped := h.sample(now, r.Level)
+
+	if dropped > 0 {
+		h.inner.Handle(droppedRecord(now, r.Level, dropped))
+	}
+
+	if pass {
+		h.inner.Handle(r)
+	}
+}
+
+func droppedRecord(now time.Time, l LogLevel, dropped int) LogRecord {
+	return LogRecord{
+		Time:    now,
+		Message: MSG_SAMPLING_DROPPED,
+		Attrs:   []LogAttr{{Key: "dropped", Value: dropped}},
+		Level:   l,
+		Context: context.Background(),
+	}
+}
+
+// Emit the counts of the records dropped in the current seconds, so they are
+// not lost if no record arrives after the seconds end.
+func (h *samplingHandler) emitDropped() {
+	now := h.now()
+
+	h.mu.Lock()
+
+	var rs []LogRecord
+	for l, c := range h.counters {
+		if c.dropped > 0 {
+			rs = append(rs, droppedRecord(now, l, c.dropped))
+			c.dropped = 0
+		}
+	}
+
+	h.mu.Unlock()
+
+	for _, r := range rs {
+		h.inner.Handle(r)
+	}
+}
+
+// Report whether the record at level 'l' passes, and the count of records
+// dropped in the last second if it's just over.
+func (h *samplingHandler) sample(
+	now time.Time,
+	l LogLevel,
+) (pass bool, dropped int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c, ok := h.counters[l]
+	if !ok {
+		c = &samplingCounter{start: now}
+		h.counters[l] = c
+	}
+
+	if now.Sub(c.start) >= time.Second {
+		dropped = c.dropped
+		*c = samplingCounter{start: now}
+	}
+
+	if c.passed < h.perSecond {
+		c.passed++
+		return true, dropped
+	}
+
+	c.dropped++
+	return false, dropped
+}
+
+func (h *samplingHandler) Flush() error {
+	h.emitDropped()
+	return flushHandler(h.inner)
+}
+
+func (h *samplingHandler) Close() error {
+	h.emitDropped()
+	return closeHandler(h.inner)
+}
+
+type perKeyCounter struct {
+	start time.Time // The beginning of the current second.
+	n     int
+}
+
+// The handler samples the records per level and message, see
+// 'NewPerKeySamplingHandler()'.
+type perKeySamplingHandler struct {
+	inner      LogHandler
+	firstN     int
+	thereafter int
+	now        func() time.Time // Replaceable in tests.
+
+	mu        sync.Mutex // Guards the fields below.
+	counters  map[messageKey]*perKeyCounter
+	lastSweep time.Time
+}
+
+// Create a handler which, for the records with the same level and message in
+// each second, passes the first 'firstN' ones to 'inner', then every
+// 'thereafterEvery'-th one, the rest are dropped. Ex: with 2 and 3, the 1st,
+// 2nd, 5th, 8th... records pass. The records after the first N are all dropped
+// if 'thereafterEvery' is not positive.
+//
+// The counters of the seconds passed are removed every second, so the memory
+// is bounded by the distinct messages in a second, ex: by the 'Infof()' with
+// the IDs.
+func NewPerKeySamplingHandler(
+	inner LogHandler,
+	firstN int,
+	thereafterEvery int,
+) LogHandler {
+	return &perKeySamplingHandler{
+		inner:      inner,
+		firstN:     firstN,
+		thereafter: thereafterEvery,
+		now:        time.Now,
+		counters:   make(map[messageKey]*perKeyCounter),
+	}
+}
+
+func (h *perKeySamplingHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *perKeySamplingHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *perKeySamplingHandler) Handle(r LogRecord) {
+	if h.sample(h.now(), messageKey{level: r.Level, msg: r.Message}) {
+		h.inner.Handle(r)
+	}
+}
+
+func (h *perKeySamplingHandler) sample(now time.Time, key messageKey) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if now.Sub(h.lastSweep) >= time.Second {
+		h.sweep(now)
+		h.lastSweep = now
+	}
+
+	c, ok := h.counters[key]
+	if !ok || now.Sub(c.start) >= time.Second {
+		c = &perKeyCounter{start: now}
+		h.counters[key] = c
+	}
+
+	c.n++
+
+	if c.n <= h.firstN {
+		return true
+	}
+
+	return h.thereafter > 0 && (c.n-h.firstN)%h.thereafter == 0
+}
+
+// Remove the counters whose seconds are over before 'now'.
+func (h *perKeySamplingHandler) sweep(now time.Time) {
+	for key, c := range h.counters {
+		if now.Sub(c.start) >= time.Second {
+			delete(h.counters, key)
+		}
+	}
+}
+
+func (h *perKeySamplingHandler) Flush() error {
+	return flushHandler(h.inner)
+}
+
+func (h *perKeySamplingHandler) Close() error {
+	return closeHandler(h.inner)
+}
@@ -0,0 +1,117 @@
+package rlog
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPerKeySamplingEvictsExpiredCounters(t *testing.T) {
+	inner, _ := NewMemoryHandler(LogLevelDebug)
+	h := NewPerKeySamplingHandler(inner, 1, 0).(*perKeySamplingHandler)
+
+	now := time.Unix(1000, 0)
+	h.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		h.Handle(LogRecord{Level: LogLevelInfo, Message: "user " + strconv.Itoa(i)})
+	}
+
+	now = now.Add(2 * time.Second)
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "later"})
+
+	if n := len(h.counters); n != 1 {
+		t.Errorf("got %d counters, want 1", n)
+	}
+}
+
+func TestSamplingFlushEmitsDroppedCount(t *testing.T) {
+	inner, snapshot := NewMemoryHandler(LogLevelDebug)
+	h := NewSamplingHandler(inner, 2).(*samplingHandler)
+
+	now := time.Unix(1000, 0)
+	h.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		h.Handle(LogRecord{Level: LogLevelInfo, Message: "burst"})
+	}
+
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := snapshot()
+	if len(rs) != 3 {
+		t.Fatalf("got %d records, want 3", len(rs))
+	}
+
+	last := rs[2]
+	if last.Message != MSG_SAMPLING_DROPPED || last.Attrs[0].Value != 3 {
+		t.Errorf("got %q %v, want 3 dropped", last.Message, last.Attrs)
+	}
+
+	// Reported once.
+	h.Flush()
+	if n := len(snapshot()); n != 3 {
+		t.Errorf("got %d records after the second flush, want 3", n)
+	}
+}
+
+func TestSamplingCapsEachLevelPerSecond(t *testing.T) {
+	inner, snapshot := NewMemoryHandler(LogLevelDebug)
+	h := NewSamplingHandler(inner, 2).(*samplingHandler)
+
+	now := time.Unix(1000, 0)
+	h.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		h.Handle(LogRecord{Level: LogLevelInfo, Message: "info"})
+		h.Handle(LogRecord{Level: LogLevelError, Message: "error"})
+	}
+
+	if n := len(snapshot()); n != 4 {
+		t.Fatalf("got %d records in the first second, want 4", n)
+	}
+
+	now = now.Add(time.Second)
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "next"})
+
+	rs := snapshot()[4:]
+	if len(rs) != 2 || rs[0].Message != MSG_SAMPLING_DROPPED ||
+		rs[0].Level != LogLevelInfo || rs[0].Attrs[0].Value != 3 ||
+		rs[1].Message != "next" {
+		t.Errorf("got %v, want 3 dropped then next", rs)
+	}
+}
+
+func TestPerKeySamplingPassesFirstNThenEveryM(t *testing.T) {
+	inner, snapshot := NewMemoryHandler(LogLevelDebug)
+	h := NewPerKeySamplingHandler(inner, 2, 3).(*perKeySamplingHandler)
+
+	now := time.Unix(1000, 0)
+	h.now = func() time.Time { return now }
+
+	for i := 1; i <= 8; i++ {
+		h.Handle(LogRecord{Level: LogLevelInfo, Message: "m", Attrs: []LogAttr{Int("i", i)}})
+	}
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "other"})
+
+	now = now.Add(time.Second)
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "m", Attrs: []LogAttr{Int("i", 9)}})
+
+	got := []any{}
+	for _, r := range snapshot() {
+		if r.Message == "m" {
+			got = append(got, r.Attrs[0].Value)
+		}
+	}
+
+	// The 1st, 2nd, 5th and 8th, then the 1st of the next second.
+	if want := []any{1, 2, 5, 8, 9}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if n := len(snapshot()); n != 6 {
+		t.Errorf("got %d records, want 6", n)
+	}
+}
@@ -0,0 +1,43 @@
+package rlog
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSequenceIsUniqueAndIncreasing(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h := NewSequenceHandler(mem)
+
+	const workers, n = 8, 100
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < n; i++ {
+				h.Handle(LogRecord{Level: LogLevelInfo, Attrs: []LogAttr{Int("w", w)}})
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	seen := map[uint64]bool{}
+	last := map[any]uint64{}
+
+	for _, r := range snapshot() {
+		w, seq := r.Attrs[0].Value, r.Attrs[1].Value.(uint64)
+		if r.Attrs[1].Key != KEY_SEQ || seen[seq] || seq <= last[w] || seq > workers*n {
+			t.Fatalf("got seq %d of worker %v after %d", seq, w, last[w])
+		}
+
+		seen[seq] = true
+		last[w] = seq
+	}
+
+	if len(seen) != workers*n {
+		t.Errorf("got %d numbers, want %d", len(seen), workers*n)
+	}
+}
@@ -0,0 +1,63 @@
+package rlog
+
+import "runtime"
+
+// The key of the stack trace attached by the stacktrace handler.
+const KEY_STACKTRACE = "stacktrace"
+
+// The handler attaches the stack trace of the calling goroutine to the records
+// at or above a level.
+type stacktraceHandler struct {
+	inner LogHandler
+	min   LogLevel
+}
+
+// Create a handler which attaches the stack trace with 'KEY_STACKTRACE' to the
+// records with level not less than 'min', then forwards them to 'inner'.
+//
+// Capturing the stack trace is expensive, so keep 'min' high, ex:
+// 'LogLevelError'.
+func NewStacktraceHandler(inner LogHandler, min LogLevel) LogHandler {
+	return &stacktraceHandler{inner: inner, min: min}
+}
+
+func (h *stacktraceHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *stacktraceHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *stacktraceHandler) Handle(r LogRecord) {
+	if r.Level >= h.min {
+		r.Attrs = concatAttrs(r.Attrs, []LogAttr{{
+			Key:   KEY_STACKTRACE,
+			Value: stacktrace(),
+		}})
+	}
+
+	h.inner.Handle(r)
+}
+
+func (h *stacktraceHandler) Flush() error {
+	return flushHandler(h.inner)
+}
+
+func (h *stacktraceHandler) Close() error {
+	return closeHandler(h.inner)
+}
+
+// Return the stack trace of the calling goroutine.
+func stacktrace() string {
+	buf := make([]byte, 4096)
+
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+
+		buf = make([]byte, len(buf)*2)
+	}
+}
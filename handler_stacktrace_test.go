@@ -0,0 +1,25 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStacktraceHandler(t *testing.T) {
+	mem, snapshot := NewMemoryHandler(LogLevelDebug)
+	logger := newLogger(NewStacktraceHandler(mem, LogLevelError))
+
+	logger.Warn("a", "k", 1)
+	logger.Error("b", "k", 1)
+
+	rs := snapshot()
+	if len(rs) != 2 || len(rs[0].Attrs) != 1 || len(rs[1].Attrs) != 2 {
+		t.Fatalf("got %v, want the trace of the error only", rs)
+	}
+
+	trace := rs[1].Attrs[1]
+	if trace.Key != KEY_STACKTRACE ||
+		!strings.Contains(trace.Value.(string), "TestStacktraceHandler") {
+		t.Errorf("got %v, want the trace of the test", trace)
+	}
+}
@@ -0,0 +1,135 @@
+//go:build !windows && !plan9
+
+package rlog
+
+import (
+	"bytes"
+	"log/syslog"
+	"sync"
+)
+
+// The handler writes the records to the syslog, the message and attributes are
+// formatted as the payload, ex: "hello k=v".
+type syslogHandler struct {
+	mu      sync.Mutex // Guards 'w' and 'closed'.
+	network string
+	addr    string
+	tag     string
+	opts    HandlerOptions
+	w       *syslog.Writer
+	closed  bool
+}
+
+// Create a handler which writes the records with level not less than 'level'
+// to the syslog server at 'addr', the arguments are same as 'syslog.Dial()'.
+func NewSyslogHandler(
+	network, addr, tag string,
+	level Leveler,
+) (LogHandler, error) {
+	return NewSyslogHandlerWithOptions(network, addr, tag,
+		&HandlerOptions{Level: level})
+}
+
+// Same as 'NewSyslogHandler()', with the options of the text handler, ex: the
+// 'OnError' is called with the records failed to write instead of writing them
+// to the stderr. A nil 'opts' is same as the zero value.
+func NewSyslogHandlerWithOptions(
+	network, addr, tag string,
+	opts *HandlerOptions,
+) (LogHandler, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &syslogHandler{
+		network: network,
+		addr:    addr,
+		tag:     tag,
+		w:       w,
+	}
+
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	if h.opts.Level == nil {
+		h.opts.Level = LogLevelInfo
+	}
+
+	return h, nil
+}
+
+func (h *syslogHandler) Enabled(l LogLevel) bool {
+	return l >= h.opts.Level.Level()
+}
+
+func (h *syslogHandler) Handle(r LogRecord) {
+	buf := bytes.Buffer{}
+
+	buf.WriteString(r.Message)
+	for _, attr := range r.Attrs {
+		writeTextAttr(&buf, &h.opts, nil, attr)
+	}
+
+	msg := buf.String()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	if err := h.write(r.Level, msg); err != nil {
+		// Reconnect and retry once, the record is dropped if still failed.
+		if err = h.reconnect(); err == nil {
+			err = h.write(r.Level, msg)
+		}
+
+		if err != nil {
+			h.opts.onError(err)
+		}
+	}
+}
+
+func (h *syslogHandler) write(l LogLevel, msg string) error {
+	switch {
+	case l >= LogLevelFatal:
+		return h.w.Crit(msg)
+	case l >= LogLevelError:
+		return h.w.Err(msg)
+	case l >= LogLevelWarn:
+		return h.w.Warning(msg)
+	case l >= LogLevelInfo:
+		return h.w.Info(msg)
+	default:
+		return h.w.Debug(msg)
+	}
+}
+
+func (h *syslogHandler) reconnect() error {
+	w, err := syslog.Dial(h.network, h.addr, syslog.LOG_USER, h.tag)
+	if err != nil {
+		return err
+	}
+
+	h.w.Close()
+	h.w = w
+
+	return nil
+}
+
+// Close the connection to the syslog server, the records handled after that
+// are dropped.
+func (h *syslogHandler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return ErrHandlerClosed
+	}
+
+	h.closed = true
+	return h.w.Close()
+}
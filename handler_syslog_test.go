@@ -0,0 +1,50 @@
+//go:build !windows && !plan9
+
+package rlog
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogHandler(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer conn.Close()
+
+	h, err := NewSyslogHandler("udp", conn.LocalAddr().String(), "app", LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Enabled(LogLevelDebug) || !h.Enabled(LogLevelWarn) {
+		t.Error("got the wrong enablement")
+	}
+
+	h.Handle(LogRecord{Level: LogLevelError, Message: "hello", Attrs: []LogAttr{Str("k", "v")}})
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The priority of LOG_USER|LOG_ERR is 8+3.
+	msg := string(buf[:n])
+	if !strings.HasPrefix(msg, "<11>") || !strings.Contains(msg, " app[") ||
+		!strings.HasSuffix(msg, "]: hello k=v\n") {
+		t.Errorf("got %q", msg)
+	}
+
+	if err := closeHandler(h); err != nil {
+		t.Fatal(err)
+	}
+	if err := closeHandler(h); err != ErrHandlerClosed {
+		t.Errorf("got %v, want ErrHandlerClosed", err)
+	}
+}
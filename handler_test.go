@@ -0,0 +1,155 @@
+package rlog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimesAreUTCByDefault(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 8*3600))
+
+	for _, tc := range []struct {
+		opts *HandlerOptions
+		want string
+	}{
+		{nil, "2024-01-01T19:04:05Z"},
+		{&HandlerOptions{}, "2024-01-01T19:04:05Z"},
+		{&HandlerOptions{LocalTime: true}, "2024-01-02T03:04:05+08:00"},
+	} {
+		buf := bytes.Buffer{}
+		NewJSONHandlerWithOptions(&buf, tc.opts).Handle(LogRecord{
+			Time:    at,
+			Level:   LogLevelInfo,
+			Message: "hi",
+			Attrs:   []LogAttr{Time("at", at)},
+		})
+
+		want := `{"time":"` + tc.want + `","level":"INFO","msg":"hi","at":"` +
+			tc.want + `"}` + "\n"
+		if buf.String() != want {
+			t.Errorf("got %s, want %s", buf.String(), want)
+		}
+	}
+}
+
+func TestTextTimesAreUTCByDefault(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 8*3600))
+
+	buf := bytes.Buffer{}
+	NewTextHandler(&buf, LogLevelInfo).Handle(LogRecord{
+		Time:    at,
+		Level:   LogLevelInfo,
+		Message: "hi",
+	})
+
+	if !strings.HasPrefix(buf.String(), "2024-01-01T19:04:05.000Z INFO hi") {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestFloatPrecision(t *testing.T) {
+	prec := func(n int) *int { return &n }
+
+	for _, tc := range []struct {
+		prec *int
+		want string
+	}{
+		{nil, "pi=3.14159"},
+		{prec(-1), "pi=3.14159"},
+		{prec(0), "pi=3"},
+		{prec(2), "pi=3.14"},
+	} {
+		buf := bytes.Buffer{}
+		NewLogfmtHandlerWithOptions(&buf, &HandlerOptions{FloatPrecision: tc.prec}).
+			Handle(LogRecord{
+				Level:   LogLevelInfo,
+				Message: "hi",
+				Attrs:   []LogAttr{Any("pi", 3.14159)},
+			})
+
+		if want := "level=INFO msg=hi " + tc.want + "\n"; buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	}
+}
+
+func TestFloatPrecisionOfTheTextHandler(t *testing.T) {
+	prec := 1
+	buf := bytes.Buffer{}
+	NewTextHandlerWithOptions(&buf, &HandlerOptions{FloatPrecision: &prec}).
+		Handle(LogRecord{
+			Level:   LogLevelInfo,
+			Message: "hi",
+			Attrs:   []LogAttr{Any("ratio", float32(0.25)), Any("n", 2)},
+		})
+
+	if want := " ratio=0.2 n=2\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("got %q, want suffix %q", buf.String(), want)
+	}
+}
+
+// The writer counts the syncs, and fails the writes if 'err' is set.
+type syncRecorder struct {
+	bytes.Buffer
+	syncs int
+	err   error
+}
+
+func (w *syncRecorder) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.Buffer.Write(p)
+}
+
+func (w *syncRecorder) Sync() error {
+	w.syncs++
+	return nil
+}
+
+func TestSyncLevel(t *testing.T) {
+	w := &syncRecorder{}
+	h := NewJSONHandlerWithOptions(w, &HandlerOptions{SyncLevel: LogLevelError})
+
+	h.Handle(LogRecord{Level: LogLevelWarn, Message: "a"})
+	if w.syncs != 0 {
+		t.Errorf("got %d syncs after warn, want 0", w.syncs)
+	}
+
+	h.Handle(LogRecord{Level: LogLevelError, Message: "b"})
+	h.Handle(LogRecord{Level: LogLevelFatal, Message: "c"})
+	if w.syncs != 2 {
+		t.Errorf("got %d syncs, want 2", w.syncs)
+	}
+
+	// Never synced by default.
+	w = &syncRecorder{}
+	NewJSONHandler(w, LogLevelInfo).Handle(LogRecord{Level: LogLevelFatal})
+	if w.syncs != 0 {
+		t.Errorf("got %d syncs without SyncLevel, want 0", w.syncs)
+	}
+}
+
+func TestOnError(t *testing.T) {
+	failed := errors.New("disk full")
+
+	var got []error
+	w := &syncRecorder{err: failed}
+	NewTextHandlerWithOptions(w, &HandlerOptions{
+		OnError: func(err error) { got = append(got, err) },
+	}).Handle(LogRecord{Level: LogLevelInfo, Message: "a"})
+
+	if len(got) != 1 || got[0] != failed {
+		t.Errorf("got errors %v, want the write error", got)
+	}
+
+	// Written to the stderr by default.
+	buf := captureStderr(t)
+	NewJSONHandler(w, LogLevelInfo).Handle(LogRecord{Level: LogLevelInfo})
+	if buf.String() != "rlog: handler failed: disk full\n" {
+		t.Errorf("got stderr %q", buf.String())
+	}
+}
@@ -0,0 +1,181 @@
+package rlog
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode"
+)
+
+// The layout of the timestamp prefix in the text records.
+const TEXT_TIME_LAYOUT = "2006-01-02T15:04:05.000Z07:00"
+
+// The handler writes each record as one line of text, ex:
+//
+//	2023-01-02T15:04:05.999Z INFO hello k=v s="with space"
+//
+// The message is quoted like the values if needed, ex: "hello world".
+//
+// And the source location is written before the message if captured, ex:
+//
+//	2023-01-02T15:04:05.999Z INFO /app/main.go:42 hello k=v
+type textHandler struct {
+	baseHandler
+	color bool // Whether the level is colorized.
+}
+
+// Create a handler which writes the records with level not less than 'level'
+// to 'w' in text. Pass a '*LevelVar' to change the level at runtime.
+func NewTextHandler(w io.Writer, level Leveler) LogHandler {
+	return NewTextHandlerWithOptions(w, &HandlerOptions{Level: level})
+}
+
+// Same as 'NewTextHandler()', with the options. A nil 'opts' is same as the
+// zero value.
+func NewTextHandlerWithOptions(w io.Writer, opts *HandlerOptions) LogHandler {
+	h := &textHandler{}
+	h.init(w, opts)
+	h.color = h.opts.Color.enabled(w)
+
+	return h
+}
+
+// Same as 'NewTextHandler()', and the level is colorized with 'ColorAuto'.
+func NewColorTextHandler(w io.Writer, level Leveler) LogHandler {
+	return NewTextHandlerWithOptions(w, &HandlerOptions{
+		Level: level,
+		Color: ColorAuto,
+	})
+}
+
+func (h *textHandler) Handle(r LogRecord) {
+	buf := bytes.Buffer{}
+
+	if !r.Time.IsZero() {
+		buf.WriteString(h.opts.timeValue(r.Time).Format(TEXT_TIME_LAYOUT))
+		buf.WriteByte(' ')
+	}
+	if h.color {
+		buf.WriteString(levelColor(r.Level))
+		buf.WriteString(r.Level.String())
+		buf.WriteString(ANSI_RESET)
+	} else {
+		buf.WriteString(r.Level.String())
+	}
+	buf.WriteByte(' ')
+	if r.Source != nil {
+		buf.WriteString(r.Source.File)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(r.Source.Line))
+		buf.WriteByte(' ')
+	}
+	// Quoted like the values, so the message never forges the lines or fields.
+	writeTextValue(&buf, r.Message)
+
+	for _, attr := range h.attrsOf(r) {
+		writeTextAttr(&buf, &h.opts, nil, attr)
+	}
+
+	buf.WriteByte('\n')
+
+	h.write(r.Level, buf.Bytes())
+}
+
+// Write the attribute as " key=value", and the attributes in a group are
+// written with the dotted keys, ex: " g.k=v". The 'groups' are the names of
+// the groups containing the attribute.
+func writeTextAttr(
+	buf *bytes.Buffer,
+	opts *HandlerOptions,
+	groups []string,
+	attr LogAttr,
+) {
+	attr = attr.Resolve()
+
+	group, ok := attr.Value.([]LogAttr)
+	if !ok {
+		if attr = opts.replaceAttr(groups, attr); attr.Key == "" {
+			return
+		}
+
+		if err, ok := attr.Value.(error); ok {
+			for _, a := range errorAttrs(attr.Key, err) {
+				writeTextField(buf, groups, a.Key, a.Value)
+			}
+			return
+		}
+
+		writeTextField(buf, groups, attr.Key, opts.textValue(attr.Value))
+		return
+	}
+
+	// The group with empty key is inlined.
+	if attr.Key != "" {
+		groups = appendGroup(groups, attr.Key)
+	}
+
+	for _, a := range group {
+		writeTextAttr(buf, opts, groups, a)
+	}
+}
+
+func writeTextField(buf *bytes.Buffer, groups []string, key string, value any) {
+	buf.WriteByte(' ')
+	for _, g := range groups {
+		writeTextKey(buf, g)
+		buf.WriteByte('.')
+	}
+	writeTextKey(buf, key)
+	buf.WriteByte('=')
+	writeTextValue(buf, value)
+}
+
+// The key is never quoted, so the characters which need quoting are replaced
+// with '_', ex: "a b" is written as "a_b".
+func writeTextKey(buf *bytes.Buffer, key string) {
+	if key == "" {
+		buf.WriteByte('_')
+		return
+	}
+
+	for _, c := range key {
+		if unicode.IsSpace(c) || !unicode.IsPrint(c) || c == '"' || c == '=' {
+			buf.WriteByte('_')
+		} else {
+			buf.WriteRune(c)
+		}
+	}
+}
+
+// Write the value in the '%+v' form, except the '[]byte' is written in base64.
+func writeTextValue(buf *bytes.Buffer, value any) {
+	var s string
+
+	if b, ok := value.([]byte); ok {
+		s = base64.StdEncoding.EncodeToString(b)
+	} else {
+		s = fmt.Sprintf("%+v", value)
+	}
+
+	if needsQuoting(s) {
+		buf.WriteString(strconv.Quote(s))
+	} else {
+		buf.WriteString(s)
+	}
+}
+
+func needsQuoting(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+
+	for _, c := range s {
+		if unicode.IsSpace(c) || !unicode.IsPrint(c) || c == '"' || c == '=' {
+			return true
+		}
+	}
+
+	return false
+}
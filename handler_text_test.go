@@ -0,0 +1,98 @@
+package rlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextHandler(t *testing.T) {
+	buf := bytes.Buffer{}
+	h := NewTextHandler(&buf, LogLevelInfo)
+
+	h.Handle(LogRecord{
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC),
+		Level:   LogLevelError,
+		Message: "failed",
+		Attrs: []LogAttr{
+			Str("s", "with space"),
+			Int("n", 1),
+			Group("g", "k", "v"),
+		},
+	})
+
+	want := `2024-01-02T03:04:05.006Z ERROR failed s="with space" n=1 g.k=v` + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	if h.Enabled(LogLevelDebug) || !h.Enabled(LogLevelInfo) {
+		t.Error("got the wrong enablement")
+	}
+}
+
+func TestTextMessageIsQuoted(t *testing.T) {
+	buf := bytes.Buffer{}
+	NewTextHandler(&buf, LogLevelInfo).Handle(LogRecord{
+		Level:   LogLevelInfo,
+		Message: "a\nINFO forged k=v",
+		Attrs:   []LogAttr{Int("n", 1)},
+	})
+
+	want := `INFO "a\nINFO forged k=v" n=1` + "\n"
+	if buf.String() != want || strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTextHandlerWritesSource(t *testing.T) {
+	buf := bytes.Buffer{}
+	NewTextHandler(&buf, LogLevelInfo).Handle(LogRecord{
+		Level:   LogLevelInfo,
+		Message: "hi",
+		Source:  &LogSource{File: "/app/main.go", Line: 42},
+	})
+
+	if want := "INFO /app/main.go:42 hi\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTextHandlerColor(t *testing.T) {
+	for _, tc := range []struct {
+		mode ColorMode
+		want string
+	}{
+		{ColorAlways, ANSI_RED + "ERROR" + ANSI_RESET + " failed\n"},
+		{ColorNever, "ERROR failed\n"},
+		// The buffer is not a terminal.
+		{ColorAuto, "ERROR failed\n"},
+	} {
+		buf := bytes.Buffer{}
+		NewTextHandlerWithOptions(&buf, &HandlerOptions{Color: tc.mode}).
+			Handle(LogRecord{Level: LogLevelError, Message: "failed"})
+
+		if buf.String() != tc.want {
+			t.Errorf("got %q with %v, want %q", buf.String(), tc.mode, tc.want)
+		}
+	}
+
+	if levelColor(LogLevelDebug) != ANSI_GRAY || levelColor(LogLevelWarn) != ANSI_YELLOW {
+		t.Error("got the wrong level colors")
+	}
+}
+
+func TestTextMaxSliceLen(t *testing.T) {
+	buf := bytes.Buffer{}
+	NewTextHandlerWithOptions(&buf, &HandlerOptions{MaxSliceLen: 2}).Handle(LogRecord{
+		Level:   LogLevelInfo,
+		Message: "m",
+		Attrs:   []LogAttr{Any("ids", []int{1, 2, 3}), Any("ok", []int{1})},
+	})
+
+	want := " ids=\"[1 2 …(+1 more)]\" ok=[1]\n"
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("got %q, want suffix %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,66 @@
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrHandlerTimeout = errors.New("rlog: handler timed out")
+
+// The handler abandons the records which the inner handler failed to handle in
+// time.
+type timeoutHandler struct {
+	inner LogHandler
+	d     time.Duration
+}
+
+// Create a handler which calls 'inner.Handle()' in a goroutine and returns
+// after 'd' at most, ex: to not hang the callers by a blocking network sink.
+// The record is dropped with a diagnostic written to stderr on timeout.
+//
+// The goroutine of the abandoned record is not stopped, it's leaked until
+// 'inner.Handle()' returns, so a sink blocked forever leaks one goroutine per
+// record.
+func NewTimeoutHandler(inner LogHandler, d time.Duration) LogHandler {
+	return &timeoutHandler{inner: inner, d: d}
+}
+
+func (h *timeoutHandler) Enabled(l LogLevel) bool {
+	return h.inner.Enabled(l)
+}
+
+func (h *timeoutHandler) CaptureSource() bool {
+	return captureSource(h.inner)
+}
+
+func (h *timeoutHandler) Handle(r LogRecord) {
+	// The record may be handled after return.
+	r = r.Clone()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		// Not recovered by the logger, since it's another goroutine.
+		handleSafely(h.inner, r)
+	}()
+
+	timer := time.NewTimer(h.d)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		reportError(fmt.Errorf("%w after %v, record dropped: %q",
+			ErrHandlerTimeout, h.d, r.Message))
+	}
+}
+
+func (h *timeoutHandler) Flush() error {
+	return flushHandler(h.inner)
+}
+
+func (h *timeoutHandler) Close() error {
+	return closeHandler(h.inner)
+}
@@ -0,0 +1,43 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutHandlerAbandonsSlowRecords(t *testing.T) {
+	buf := captureStderr(t)
+
+	inner := blockingHandler{release: make(chan struct{})}
+	defer close(inner.release)
+
+	h := NewTimeoutHandler(inner, 20*time.Millisecond)
+
+	start := time.Now()
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "slow"})
+
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("returned after %v, want about 20ms", d)
+	}
+	if got := buf.String(); !strings.Contains(got, ErrHandlerTimeout.Error()) ||
+		!strings.Contains(got, `"slow"`) {
+		t.Errorf("got %q, want the timeout diagnostic", got)
+	}
+}
+
+func TestTimeoutHandlerPassesFastRecords(t *testing.T) {
+	buf := captureStderr(t)
+
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h := NewTimeoutHandler(mem, time.Second)
+
+	h.Handle(LogRecord{Level: LogLevelInfo, Message: "fast"})
+
+	if rs := snapshot(); len(rs) != 1 || rs[0].Message != "fast" {
+		t.Errorf("got %v, want fast", rs)
+	}
+	if got := buf.String(); got != "" {
+		t.Errorf("got diagnostic %q, want none", got)
+	}
+}
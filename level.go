@@ -0,0 +1,72 @@
+package rlog
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+var levelNames = map[LogLevel]string{
+	LogLevelDebug: "DEBUG",
+	LogLevelInfo:  "INFO",
+	LogLevelWarn:  "WARN",
+	LogLevelError: "ERROR",
+	LogLevelFatal: "FATAL",
+}
+
+// Return the canonical name of the level, ex: "INFO".
+func (l LogLevel) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("LEVEL(%d)", int8(l))
+}
+
+// Parse the level from its name, case-insensitive. Ex: "debug", "INFO".
+func ParseLevel(s string) (LogLevel, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+
+	for l, n := range levelNames {
+		if n == name {
+			return l, nil
+		}
+	}
+
+	return LogLevelInfo, fmt.Errorf("rlog: unknown log level %q", s)
+}
+
+// The Leveler provides the minimum level of the records to be handled.
+//
+// Both 'LogLevel' and '*LevelVar' implement it, use the former for a fixed
+// level or the latter for a level could be changed at runtime.
+type Leveler interface {
+	Level() LogLevel
+}
+
+func (l LogLevel) Level() LogLevel {
+	return l
+}
+
+// The LevelVar is a level variable which is safe for concurrent use, the
+// handlers created with it see the new level once 'Set()' returns. The zero
+// value is 'LogLevelInfo'.
+type LevelVar struct {
+	v int32
+}
+
+func NewLevelVar(l LogLevel) *LevelVar {
+	return &LevelVar{v: int32(l)}
+}
+
+func (v *LevelVar) Level() LogLevel {
+	return LogLevel(atomic.LoadInt32(&v.v))
+}
+
+func (v *LevelVar) Set(l LogLevel) {
+	atomic.StoreInt32(&v.v, int32(l))
+}
+
+func (v *LevelVar) String() string {
+	return fmt.Sprintf("LevelVar(%s)", v.Level())
+}
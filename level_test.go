@@ -0,0 +1,41 @@
+package rlog
+
+import "testing"
+
+func TestLevelStringAndParse(t *testing.T) {
+	for _, l := range []LogLevel{
+		LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelFatal,
+	} {
+		got, err := ParseLevel(" " + l.String() + " ")
+		if err != nil || got != l {
+			t.Errorf("got %v and %v, want %v", got, err, l)
+		}
+	}
+
+	if l, err := ParseLevel("warn"); err != nil || l != LogLevelWarn {
+		t.Errorf("got %v and %v, want WARN", l, err)
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("got no error of the unknown level")
+	}
+	if s := LogLevel(42).String(); s != "LEVEL(42)" {
+		t.Errorf("got %q", s)
+	}
+}
+
+func TestLevelVarChangesRegisteredHandler(t *testing.T) {
+	v := NewLevelVar(LogLevelWarn)
+	logger, snapshot := newTestLogger(t, v)
+
+	logger.Info("dropped")
+	v.Set(LogLevelDebug)
+	logger.Debug("kept")
+
+	if rs := snapshot(); len(rs) != 1 || rs[0].Message != "kept" {
+		t.Errorf("got %v, want the debug record only", rs)
+	}
+
+	if (&LevelVar{}).Level() != LogLevelInfo {
+		t.Error("got the zero value not INFO")
+	}
+}
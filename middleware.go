@@ -0,0 +1,105 @@
+package rlog
+
+import "time"
+
+// The Middleware wraps a handler and returns the wrapper, ex: to filter or
+// sample the records before the inner handler.
+type Middleware func(LogHandler) LogHandler
+
+// Wrap 'base' with the middlewares, the first one is the outermost, so the
+// records pass 'mws' in order then reach 'base'. Ex:
+//
+//	Chain(base, FilterMiddleware(pred), SamplingMiddleware(100))
+//
+// is same as:
+//
+//	NewFilterHandler(NewSamplingHandler(base, 100), pred)
+func Chain(base LogHandler, mws ...Middleware) LogHandler {
+	h := base
+
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+
+	return h
+}
+
+// The middlewares of the wrapper handlers, see the constructors of them for
+// the details.
+
+func FilterMiddleware(pred func(LogRecord) bool) Middleware {
+	return func(inner LogHandler) LogHandler {
+		return NewFilterHandler(inner, pred)
+	}
+}
+
+func ModuleLevelMiddleware(levels map[string]LogLevel, fallback LogLevel) Middleware {
+	return func(inner LogHandler) LogHandler {
+		return NewModuleLevelHandler(inner, levels, fallback)
+	}
+}
+
+func SamplingMiddleware(perSecond int) Middleware {
+	return func(inner LogHandler) LogHandler {
+		return NewSamplingHandler(inner, perSecond)
+	}
+}
+
+func PerKeySamplingMiddleware(firstN, thereafterEvery int) Middleware {
+	return func(inner LogHandler) LogHandler {
+		return NewPerKeySamplingHandler(inner, firstN, thereafterEvery)
+	}
+}
+
+func DedupMiddleware(window time.Duration) Middleware {
+	return func(inner LogHandler) LogHandler {
+		return NewDedupHandler(inner, window)
+	}
+}
+
+func StacktraceMiddleware(min LogLevel) Middleware {
+	return func(inner LogHandler) LogHandler {
+		return NewStacktraceHandler(inner, min)
+	}
+}
+
+func GoroutineIDMiddleware() Middleware {
+	return NewGoroutineIDHandler
+}
+
+func SequenceMiddleware() Middleware {
+	return NewSequenceHandler
+}
+
+func HashMiddleware() Middleware {
+	return NewHashHandler
+}
+
+// The returned handler implements 'Closer', call 'CloseAll()' or its 'Close()'
+// to stop the background goroutine.
+func AsyncMiddleware(bufferSize int, policy AsyncPolicy) Middleware {
+	return func(inner LogHandler) LogHandler {
+		h, _ := NewAsyncHandlerWithPolicy(inner, bufferSize, policy)
+		return h
+	}
+}
+
+// Same as 'AsyncMiddleware()', the returned handler implements 'Closer'.
+func BatchMiddleware(maxBatch int, flushInterval time.Duration) Middleware {
+	return func(inner LogHandler) LogHandler {
+		h, _ := NewBatchHandler(inner, maxBatch, flushInterval)
+		return h
+	}
+}
+
+func FieldsMiddleware(fields ...LogAttr) Middleware {
+	return func(inner LogHandler) LogHandler {
+		return NewWithFields(inner, fields...)
+	}
+}
+
+func TimeoutMiddleware(d time.Duration) Middleware {
+	return func(inner LogHandler) LogHandler {
+		return NewTimeoutHandler(inner, d)
+	}
+}
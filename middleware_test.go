@@ -0,0 +1,64 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return FilterMiddleware(func(LogRecord) bool {
+			order = append(order, name)
+			return true
+		})
+	}
+
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h := Chain(mem, tag("a"), tag("b"), FieldsMiddleware(Str("k", "v")))
+
+	newLogger(h).Info("m")
+
+	if got := strings.Join(order, " "); got != "a b" {
+		t.Errorf("got order %q, want a b", got)
+	}
+	if rs := snapshot(); len(rs) != 1 || rs[0].Attrs[0].Key != "k" {
+		t.Errorf("got %v, want the fields", rs)
+	}
+	if Chain(mem) != mem {
+		t.Error("got the base wrapped without the middlewares")
+	}
+}
+
+func TestChainFiltersBeforeSampling(t *testing.T) {
+	calls := 0
+	count := func(inner LogHandler) LogHandler {
+		return NewFilterHandler(inner, func(LogRecord) bool {
+			calls++
+			return true
+		})
+	}
+
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h := Chain(mem,
+		FilterMiddleware(func(r LogRecord) bool { return r.Message != "noisy" }),
+		count,
+		SamplingMiddleware(2),
+	)
+
+	logger := newLogger(h)
+	for i := 0; i < 5; i++ {
+		logger.Info("noisy")
+	}
+	logger.Info("kept")
+	logger.Info("kept")
+
+	// The sampler only sees the records passed the filter, so it never spends
+	// the budget on the filtered ones.
+	if calls != 2 {
+		t.Errorf("got %d records behind the filter, want 2", calls)
+	}
+	if rs := snapshot(); len(rs) != 2 || rs[0].Message != "kept" || rs[1].Message != "kept" {
+		t.Errorf("got %v, want 2 kept", rs)
+	}
+}
@@ -0,0 +1,49 @@
+package rlog
+
+import "context"
+
+// The logger returned when no handler is registered under the requested name,
+// all records are dropped silently.
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, args ...any) {}
+
+func (nopLogger) Info(msg string, args ...any) {}
+
+func (nopLogger) Warn(msg string, args ...any) {}
+
+func (nopLogger) Error(msg string, args ...any) {}
+
+func (l nopLogger) With(args ...any) ILogger { return l }
+
+func (l nopLogger) WithGroup(name string) ILogger { return l }
+
+func (l nopLogger) WithLevel(min LogLevel) ILogger { return l }
+
+func (nopLogger) Name() string { return "" }
+
+func (nopLogger) Enabled(level LogLevel) bool { return false }
+
+func (nopLogger) DebugCtx(ctx context.Context, msg string, args ...any) {}
+
+func (nopLogger) InfoCtx(ctx context.Context, msg string, args ...any) {}
+
+func (nopLogger) WarnCtx(ctx context.Context, msg string, args ...any) {}
+
+func (nopLogger) ErrorCtx(ctx context.Context, msg string, args ...any) {}
+
+func (nopLogger) Debugf(format string, args ...any) {}
+
+func (nopLogger) Infof(format string, args ...any) {}
+
+func (nopLogger) Warnf(format string, args ...any) {}
+
+func (nopLogger) Errorf(format string, args ...any) {}
+
+func (nopLogger) Log(level LogLevel, msg string, args ...any) {}
+
+// Even no record is written, the no-op logger keeps the control flow of the
+// fatal and panic methods.
+func (nopLogger) Fatal(msg string, args ...any) { exitFunc(1) }
+
+func (nopLogger) Panic(msg string, args ...any) { panic(msg) }
@@ -0,0 +1,46 @@
+package rlog
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUnregisteredLoggerIsSafe(t *testing.T) {
+	withPendingBufferSize(t, 0)
+
+	logger, ok := LookupLogger(t.Name())
+	if ok || logger == nil {
+		t.Fatalf("got %v and %v, want a no-op logger", logger, ok)
+	}
+
+	logger = logger.With("k", "v").WithGroup("g").WithLevel(LogLevelDebug)
+	logger.Debug("a")
+	logger.Infof("b %d", 1)
+	logger.ErrorCtx(context.Background(), "c")
+	logger.Log(LogLevelWarn, "d")
+
+	if logger.Enabled(LogLevelFatal) {
+		t.Error("got enabled")
+	}
+}
+
+func TestNopLoggerKeepsFatalControlFlow(t *testing.T) {
+	old := exitFunc
+	defer func() { exitFunc = old }()
+
+	code := -1
+	exitFunc = func(c int) { code = c }
+
+	nopLogger{}.Fatal("bye")
+	if code != 1 {
+		t.Errorf("got exit code %d, want 1", code)
+	}
+
+	defer func() {
+		if recover() != "boom" {
+			t.Error("got no panic")
+		}
+	}()
+
+	nopLogger{}.Panic("boom")
+}
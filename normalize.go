@@ -0,0 +1,21 @@
+package rlog
+
+var keyNormalizer atomicBox[func(string) string]
+
+// Set the function applied to the keys of the attributes passed to the log
+// methods and 'With()', ex: to convert the "camelCase" keys to "snake_case", so
+// the downstream schemas are consistent. Pass nil to keep the keys as is, which
+// is the default.
+//
+// The keys of the attributes bound before this are not changed.
+func SetKeyNormalizer(fn func(string) string) {
+	keyNormalizer.Store(fn)
+}
+
+func normalizeKey(key string) string {
+	if fn := keyNormalizer.Load(); fn != nil {
+		return fn(key)
+	}
+
+	return key
+}
@@ -0,0 +1,34 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyNormalizer(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+	bound := logger.With("boundKey", 0)
+
+	SetKeyNormalizer(strings.ToLower)
+	t.Cleanup(func() { SetKeyNormalizer(nil) })
+
+	logger.With("userID", 1).Info("a", "reqID", 2, Int("statusCode", 3),
+		map[string]any{"mapKey": 4})
+	bound.Info("b")
+
+	SetKeyNormalizer(nil)
+	logger.Info("c", "userID", 5)
+
+	keys := []string{}
+	for _, r := range snapshot() {
+		for _, a := range r.Attrs {
+			keys = append(keys, a.Key)
+		}
+	}
+
+	// The bound keys are kept.
+	want := "userid reqid statuscode mapkey boundKey userID"
+	if got := strings.Join(keys, " "); got != want {
+		t.Errorf("got keys %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+package rlog
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// The depth of the nested structs expanded by 'Object()', the deeper ones are
+// kept as the values.
+const maxObjectDepth = 8
+
+// The value of the pointer to a struct being expanded by 'Object()'.
+const OBJECT_CYCLE = "!CYCLE"
+
+// Return an attribute of the struct 'v' expanded to a group, and the exported
+// fields are the attributes in it, ex:
+//
+//	type User struct {
+//		Name string `json:"name"`
+//		Addr Addr   `json:"addr"`
+//	}
+//
+//	Object("user", u) // user.name=bob user.addr.city=paris
+//
+// The names in the 'json' tags are used as the keys, and the fields tagged
+// with "-" are skipped. The nested structs and pointers to them are expanded
+// too, except the 'time.Time', 'error' and 'json.Marshaler' which are kept as
+// the values. The pointer to a struct being expanded is written as
+// 'OBJECT_CYCLE'. If 'v' is not a struct, same as 'Any()'.
+func Object(key string, v any) LogAttr {
+	attrs, ok := objectAttrs(reflect.ValueOf(v), 0, map[uintptr]bool{})
+	if !ok {
+		return Any(key, v)
+	}
+
+	return LogAttr{Key: key, Value: attrs}
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Return the attributes of the struct fields, 'ok' is false if 'v' is not a
+// struct or a pointer to it. The 'visiting' are the pointers being expanded.
+func objectAttrs(
+	v reflect.Value,
+	depth int,
+	visiting map[uintptr]bool,
+) (attrs []LogAttr, ok bool) {
+	if !v.IsValid() || depth >= maxObjectDepth || keptAsValue(v.Type()) {
+		return nil, false
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return nil, false
+		}
+
+		ptr := v.Pointer()
+		visiting[ptr] = true
+		defer delete(visiting, ptr)
+
+		v = v.Elem()
+		if keptAsValue(v.Type()) {
+			return nil, false
+		}
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	t := v.Type()
+	attrs = make([]LogAttr, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, skip := fieldName(f)
+		if skip {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Pointer && !fv.IsNil() && visiting[fv.Pointer()] {
+			attrs = append(attrs, LogAttr{Key: name, Value: OBJECT_CYCLE})
+			continue
+		}
+
+		if group, ok := objectAttrs(fv, depth+1, visiting); ok {
+			// The embedded struct without name is inlined, same as 'json'.
+			if f.Anonymous && name == f.Name {
+				name = ""
+			}
+
+			attrs = append(attrs, LogAttr{Key: name, Value: group})
+			continue
+		}
+
+		attrs = append(attrs, LogAttr{Key: name, Value: fv.Interface()})
+	}
+
+	return attrs, true
+}
+
+// The types written as the values rather than expanded.
+func keptAsValue(t reflect.Type) bool {
+	return t == timeType || t.Implements(errorType) || t.Implements(marshalerType)
+}
+
+// Return the name of the field in the 'json' tag, or the field name.
+func fieldName(f reflect.StructField) (name string, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	if name, _, _ = strings.Cut(tag, ","); name == "" {
+		name = f.Name
+	}
+
+	return name, false
+}
@@ -0,0 +1,80 @@
+package rlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type testAddr struct {
+	City string `json:"city"`
+	Zip  string `json:"-"`
+}
+
+type testUser struct {
+	Name   string `json:"name,omitempty"`
+	Age    int
+	Addr   testAddr `json:"addr"`
+	secret string
+}
+
+type testNode struct {
+	ID   int
+	Next *testNode
+}
+
+func TestObjectExpandsNestedStructs(t *testing.T) {
+	u := testUser{
+		Name:   "bob",
+		Age:    30,
+		Addr:   testAddr{City: "paris", Zip: "750"},
+		secret: "s",
+	}
+
+	buf := bytes.Buffer{}
+	NewLogfmtHandler(&buf, LogLevelInfo).Handle(LogRecord{
+		Level:   LogLevelInfo,
+		Message: "m",
+		Attrs:   []LogAttr{Object("user", &u)},
+	})
+
+	want := "msg=m user.name=bob user.Age=30 user.addr.city=paris\n"
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("got %q, want suffix %q", buf.String(), want)
+	}
+
+	if a := Object("n", 1); a != (LogAttr{Key: "n", Value: 1}) {
+		t.Errorf("got %v, want the plain value", a)
+	}
+}
+
+func TestObjectGuardsCyclesAndDepth(t *testing.T) {
+	a := &testNode{ID: 1}
+	a.Next = &testNode{ID: 2, Next: a}
+
+	attrs := Object("a", a).Value.([]LogAttr)
+	next := attrs[1].Value.([]LogAttr)
+	if next[0] != (LogAttr{Key: "ID", Value: 2}) ||
+		next[1] != (LogAttr{Key: "Next", Value: OBJECT_CYCLE}) {
+		t.Errorf("got %v, want the cycle marked", next)
+	}
+
+	// A list deeper than the limit.
+	head := &testNode{}
+	for n, i := head, 0; i < 2*maxObjectDepth; i++ {
+		n.Next = &testNode{ID: i + 1}
+		n = n.Next
+	}
+
+	depth := 0
+	for v := Object("head", head).Value; ; depth++ {
+		group, ok := v.([]LogAttr)
+		if !ok {
+			break
+		}
+		v = group[1].Value
+	}
+	if depth != maxObjectDepth {
+		t.Errorf("got depth %d, want %d", depth, maxObjectDepth)
+	}
+}
@@ -0,0 +1,25 @@
+package rlog
+
+import "time"
+
+// The Observer is notified for each record emitted by the loggers, ex: to count
+// the log volume and measure the latency of the handlers.
+//
+// The record passed to 'ObserveRecord()' follows the same contract as
+// 'LogHandler.Handle()', clone it before retaining.
+type Observer interface {
+	ObserveRecord(r LogRecord)
+	ObserveHandleLatency(d time.Duration)
+}
+
+var observer atomicBox[Observer]
+
+// Set the observer notified before and after the handler handles each record.
+// Pass nil to remove it, which is the default.
+func SetObserver(o Observer) {
+	observer.Store(o)
+}
+
+func loadObserver() Observer {
+	return observer.Load()
+}
@@ -0,0 +1,56 @@
+package rlog
+
+import (
+	"testing"
+	"time"
+)
+
+// The observer keeps the messages and latencies.
+type testObserver struct {
+	messages  []string
+	latencies []time.Duration
+}
+
+func (o *testObserver) ObserveRecord(r LogRecord) {
+	o.messages = append(o.messages, r.Message)
+}
+
+func (o *testObserver) ObserveHandleLatency(d time.Duration) {
+	o.latencies = append(o.latencies, d)
+}
+
+// The handler sleeps in each call.
+type slowHandler struct {
+	discardHandler
+	d time.Duration
+}
+
+func (h slowHandler) Handle(LogRecord) { time.Sleep(h.d) }
+
+func TestObserverIsNotified(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+	RegisterLogHandler(t.Name(), slowHandler{d: time.Millisecond})
+
+	o := &testObserver{}
+	SetObserver(o)
+	t.Cleanup(func() { SetObserver(nil) })
+
+	logger := GetLogger(t.Name())
+	logger.Info("a")
+	logger.Debug("b")
+
+	SetObserver(nil)
+	logger.Info("unobserved")
+
+	if len(o.messages) != 2 || o.messages[0] != "a" || o.messages[1] != "b" {
+		t.Errorf("got messages %v, want a and b", o.messages)
+	}
+	for _, d := range o.latencies {
+		if d < time.Millisecond {
+			t.Errorf("got latency %v, want at least 1ms", d)
+		}
+	}
+	if len(o.latencies) != 2 {
+		t.Errorf("got %d latencies, want 2", len(o.latencies))
+	}
+}
@@ -0,0 +1,205 @@
+package rlog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// The maximum number of the names buffering the records, the loggers of the
+// names beyond that drop all records, so the dynamic names never grow the
+// memory without bound.
+const maxPendingNames = 64
+
+// The default number of the records buffered per name before the handler is
+// registered, see 'SetPendingBufferSize()'.
+const DEFAULT_PENDING_BUFFER_SIZE = 32
+
+// The number of the records buffered per name before the registration, the
+// buffering is disabled if 0.
+var pendingBufferSize int32 = DEFAULT_PENDING_BUFFER_SIZE
+
+var (
+	pendingsMu sync.Mutex                     // Guards 'pendings'.
+	pendings   = map[string]*pendingHandler{} // The names not replayed yet.
+)
+
+// Set the number of the records buffered for each name before the handler is
+// registered, which keeps the records logged in the early init. It's
+// 'DEFAULT_PENDING_BUFFER_SIZE' by default, pass 0 to disable the buffering,
+// then the loggers of the names not registered drop all records.
+//
+// The buffered records are replayed to the first handler registered under the
+// name, and the records exceeding the size are dropped with a warning written
+// to the stderr on replay. At most 64 names are buffered, and the debug records
+// are never buffered.
+func SetPendingBufferSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	atomic.StoreInt32(&pendingBufferSize, int32(n))
+}
+
+// The handler of the loggers got before the registration, it buffers the
+// records until the handler is registered under the name, then forwards the
+// records to it.
+type pendingHandler struct {
+	name string
+
+	mu       sync.Mutex // Guards 'records', 'dropped' and 'replayed'.
+	records  []LogRecord
+	dropped  int
+	replayed bool // The records are dropped if unregistered after the replay.
+}
+
+// Return the pending handler of the name, nil if the buffering is disabled or
+// too many names are buffering.
+func loadPendingHandler(name string) *pendingHandler {
+	if atomic.LoadInt32(&pendingBufferSize) == 0 {
+		return nil
+	}
+
+	pendingsMu.Lock()
+	defer pendingsMu.Unlock()
+
+	if h, ok := pendings[name]; ok {
+		return h
+	}
+
+	if len(pendings) >= maxPendingNames {
+		return nil
+	}
+
+	h := &pendingHandler{name: name}
+	pendings[name] = h
+
+	return h
+}
+
+func (h *pendingHandler) registered() *registration {
+	if v, ok := loggers.Load(h.name); ok {
+		return v.(*registration)
+	}
+
+	return nil
+}
+
+// The level of the handler to be registered is unknown, so the records at or
+// above 'LogLevelInfo' are buffered, which is the default level of the
+// built-in handlers.
+func (h *pendingHandler) Enabled(l LogLevel) bool {
+	if reg := h.registered(); reg != nil {
+		return reg.h.Enabled(l)
+	}
+
+	return l >= LogLevelInfo
+}
+
+func (h *pendingHandler) CaptureSource() bool {
+	if reg := h.registered(); reg != nil {
+		return captureSource(reg.h)
+	}
+
+	return false
+}
+
+func (h *pendingHandler) Handle(r LogRecord) {
+	h.mu.Lock()
+
+	reg := h.registered()
+	if reg == nil {
+		if !h.replayed {
+			h.buffer(r)
+		}
+
+		h.mu.Unlock()
+		return
+	}
+
+	// The registration may be visible before 'replayPending()' is called, so
+	// the buffered records are replayed here first to keep them in order.
+	h.replayLocked(reg)
+	h.mu.Unlock()
+
+	reg.h.Handle(reg.withAttrs(r))
+}
+
+func (h *pendingHandler) buffer(r LogRecord) {
+	if len(h.records) >= int(atomic.LoadInt32(&pendingBufferSize)) {
+		h.dropped++
+		return
+	}
+
+	// The attributes are reused once this returns, and the context is not
+	// retained, ex: the values of a finished request.
+	r = r.Clone()
+	r.Context = context.Background()
+
+	h.records = append(h.records, r)
+}
+
+// Pass the buffered records to the registered handler once, and release the
+// name, so it's not counted by 'maxPendingNames'.
+func (h *pendingHandler) replayLocked(reg *registration) {
+	if h.replayed {
+		return
+	}
+
+	h.replayed = true
+
+	pendingsMu.Lock()
+	if pendings[h.name] == h {
+		delete(pendings, h.name)
+	}
+	pendingsMu.Unlock()
+
+	if h.dropped > 0 {
+		fmt.Fprintf(stderr, "rlog: %d records logged before %q was registered "+
+			"are dropped\n", h.dropped, h.name)
+	}
+
+	for _, r := range h.records {
+		if reg.h.Enabled(r.Level) {
+			handleSafely(reg.h, reg.withAttrs(r))
+		}
+	}
+
+	h.records = nil
+	h.dropped = 0
+}
+
+// Replay the records buffered for the name to the registration.
+func replayPending(name string, reg *registration) {
+	pendingsMu.Lock()
+	h := pendings[name]
+	pendingsMu.Unlock()
+
+	if h != nil {
+		h.mu.Lock()
+		h.replayLocked(reg)
+		h.mu.Unlock()
+	}
+}
+
+// Return the record with the attributes bound by the registration inserted,
+// after the module attribute if any, same as the loggers got after the
+// registration.
+func (reg *registration) withAttrs(r LogRecord) LogRecord {
+	if len(reg.attrs) == 0 {
+		return r
+	}
+
+	n := 0
+	if len(r.Attrs) > 0 && r.Attrs[0].Key == KEY_MODULE {
+		n = 1
+	}
+
+	attrs := make([]LogAttr, 0, len(r.Attrs)+len(reg.attrs))
+	attrs = append(attrs, r.Attrs[:n]...)
+	attrs = append(attrs, reg.attrs...)
+	r.Attrs = append(attrs, r.Attrs[n:]...)
+
+	return r
+}
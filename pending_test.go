@@ -0,0 +1,158 @@
+package rlog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Set the buffer size until the test ends.
+func withPendingBufferSize(t *testing.T, n int) {
+	t.Helper()
+	t.Cleanup(SnapshotRegistry())
+
+	SetPendingBufferSize(n)
+	t.Cleanup(func() { SetPendingBufferSize(DEFAULT_PENDING_BUFFER_SIZE) })
+}
+
+func TestPendingIsEnabledByDefault(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	GetLogger(t.Name()).Info("a")
+
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandler(t.Name(), h)
+
+	if rs := snapshot(); len(rs) != 1 || rs[0].Message != "a" {
+		t.Errorf("got %v, want a replayed", rs)
+	}
+}
+
+func TestUnregisteredDropsIfDisabled(t *testing.T) {
+	withPendingBufferSize(t, 0)
+
+	logger, ok := LookupLogger(t.Name())
+	if ok {
+		t.Fatal("got registered")
+	}
+	logger.Info("dropped")
+
+	h, snapshot := NewMemoryHandler(LogLevelDebug)
+	RegisterLogHandler(t.Name(), h)
+
+	if rs := snapshot(); len(rs) != 0 {
+		t.Errorf("got %d records replayed, want 0", len(rs))
+	}
+}
+
+func TestPendingRecordsAreReplayed(t *testing.T) {
+	withPendingBufferSize(t, 2)
+	buf := captureStderr(t)
+
+	logger := GetLoggerWithModule(t.Name(), "db")
+	if logger.Enabled(LogLevelDebug) {
+		t.Error("debug enabled before the registration")
+	}
+
+	logger.Debug("never buffered")
+	logger.Info("a", "k", 1)
+	logger.Warn("b", "k", 1)
+	logger.Error("dropped")
+
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandlerWithAttrs(t.Name(), h, Str("app", "x"))
+	logger.Info("c", "k", 1)
+	GetLoggerWithModule(t.Name(), "db").Info("live", "k", 1)
+
+	rs := snapshot()
+	if len(rs) != 4 {
+		t.Fatalf("got %d records, want 4", len(rs))
+	}
+
+	// Same order as the loggers got after the registration.
+	for i, msg := range []string{"a", "b", "c", "live"} {
+		keys := []string{}
+		for _, a := range rs[i].Attrs {
+			keys = append(keys, a.Key)
+		}
+
+		got := strings.Join(keys, " ")
+		if rs[i].Message != msg || got != "module app k" {
+			t.Errorf("got %q with %q, want %q with module app k", rs[i].Message, got, msg)
+		}
+	}
+	if !strings.Contains(buf.String(), "1 records logged before") {
+		t.Errorf("got stderr %q", buf.String())
+	}
+}
+
+// The records handled once the registration is visible are forwarded after
+// the buffered ones, even if the replay of the registration is not done yet.
+func TestPendingReplaysBeforeForwarding(t *testing.T) {
+	withPendingBufferSize(t, 10)
+
+	logger := GetLogger(t.Name())
+	logger.Info("a")
+
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+	reg := &registration{h: h}
+	loggers.Store(t.Name(), reg)
+
+	logger.Info("b")
+	replayPending(t.Name(), reg)
+
+	rs := snapshot()
+	if len(rs) != 2 || rs[0].Message != "a" || rs[1].Message != "b" {
+		t.Errorf("got records %+v, want a and b", rs)
+	}
+}
+
+func TestPendingNamesAreBounded(t *testing.T) {
+	withPendingBufferSize(t, 10)
+
+	for i := 0; i < maxPendingNames; i++ {
+		if _, ok := GetLogger(fmt.Sprintf("%s-%d", t.Name(), i)).(nopLogger); ok {
+			t.Fatalf("got no-op logger for name %d", i)
+		}
+	}
+
+	if _, ok := GetLogger(t.Name()).(nopLogger); !ok {
+		t.Error("got buffering logger beyond the bound")
+	}
+
+	// The replayed names are released.
+	h, _ := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandler(t.Name()+"-0", h)
+
+	if _, ok := GetLogger(t.Name()).(nopLogger); ok {
+		t.Error("got no-op logger after a name is released")
+	}
+}
+
+func TestPendingReplaysToTheFirstHandlerOnly(t *testing.T) {
+	withPendingBufferSize(t, 4)
+
+	logger := GetLogger(t.Name())
+	attrs := []LogAttr{Int("i", 0)}
+	logger.Info("a", attrs)
+
+	// The buffered attributes are copied.
+	attrs[0].Value = 1
+
+	first, firstRecords := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandler(t.Name(), first)
+	UnregisterLogHandler(t.Name())
+
+	// Dropped once replayed, rather than buffered again.
+	logger.Info("dropped")
+
+	second, secondRecords := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandler(t.Name(), second)
+
+	if rs := firstRecords(); len(rs) != 1 || rs[0].Attrs[0].Value != 0 {
+		t.Errorf("got %v, want a with i=0", rs)
+	}
+	if rs := secondRecords(); len(rs) != 0 {
+		t.Errorf("got %v replayed again", rs)
+	}
+}
@@ -0,0 +1,81 @@
+package rlog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordCloneIsDeep(t *testing.T) {
+	r := LogRecord{
+		Level:   LogLevelInfo,
+		Message: "m",
+		Attrs:   []LogAttr{Str("k", "v"), Group("g", "n", 1)},
+	}
+
+	c := r.Clone()
+	r.Attrs[0].Value = "changed"
+	r.Attrs[1].Value.([]LogAttr)[0].Value = 2
+
+	if c.Attrs[0].Value != "v" || c.Attrs[1].Value.([]LogAttr)[0].Value != 1 {
+		t.Errorf("got %v, want the clone unchanged", c.Attrs)
+	}
+	if (LogRecord{}).Clone().Attrs != nil {
+		t.Error("got attrs of the empty record")
+	}
+}
+
+func TestRecordToMap(t *testing.T) {
+	at := time.Unix(0, 0)
+	m := LogRecord{
+		Time:    at,
+		Level:   LogLevelWarn,
+		Message: "m",
+		Attrs: []LogAttr{
+			Str("k", "v"),
+			Group("g", "n", 1),
+			Group("", "inlined", true),
+			Any("lazy", Valuer(func() any { return "resolved" })),
+		},
+	}.ToMap()
+
+	want := map[string]any{
+		KEY_TIME:    at,
+		KEY_LEVEL:   "WARN",
+		KEY_MESSAGE: "m",
+		"k":         "v",
+		"g":         map[string]any{"n": 1},
+		"inlined":   true,
+		"lazy":      "resolved",
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v, want %v", m, want)
+	}
+}
+
+func TestRecordToMapMergesGroups(t *testing.T) {
+	src := &LogSource{File: "a.go", Line: 1}
+	m := LogRecord{
+		Level:   LogLevelInfo,
+		Message: "m",
+		Source:  src,
+		Attrs: []LogAttr{
+			Group("db", "host", "h", Group("pool", "size", 2)),
+			Group("db", "port", 5432),
+		},
+	}.ToMap()
+
+	want := map[string]any{
+		KEY_LEVEL:   "INFO",
+		KEY_SOURCE:  src,
+		KEY_MESSAGE: "m",
+		"db": map[string]any{
+			"host": "h",
+			"pool": map[string]any{"size": 2},
+			"port": 5432,
+		},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v, want %v", m, want)
+	}
+}
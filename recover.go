@@ -0,0 +1,35 @@
+package rlog
+
+// The key of the recovered panic value.
+const KEY_PANIC = "panic"
+
+// The message of the records logged for the recovered panics.
+const MSG_PANIC_RECOVERED = "panic recovered"
+
+// Log the active panic at 'LogLevelError' with the value and stack trace, then
+// panic again with the same value. It must be deferred directly, ex:
+//
+//	go func() {
+//		defer rlog.Recover(logger)
+//		...
+//	}()
+//
+// Nothing happens if there is no panic.
+func Recover(l ILogger) {
+	if v := recover(); v != nil {
+		logPanic(l, v)
+		panic(v)
+	}
+}
+
+// Same as 'Recover()', but the panic is swallowed, so the goroutine returns
+// normally.
+func RecoverAndContinue(l ILogger) {
+	if v := recover(); v != nil {
+		logPanic(l, v)
+	}
+}
+
+func logPanic(l ILogger, v any) {
+	l.Error(MSG_PANIC_RECOVERED, KEY_PANIC, v, KEY_STACKTRACE, stacktrace())
+}
@@ -0,0 +1,51 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecoverLogsAndPanicsAgain(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Errorf("got panic %v, want boom", v)
+			}
+		}()
+		defer Recover(logger)
+
+		panic("boom")
+	}()
+
+	rs := snapshot()
+	if len(rs) != 1 || rs[0].Level != LogLevelError || rs[0].Message != MSG_PANIC_RECOVERED {
+		t.Fatalf("got %v, want one panic record", rs)
+	}
+
+	attrs := rs[0].ToMap()
+	stack, _ := attrs[KEY_STACKTRACE].(string)
+	if attrs[KEY_PANIC] != "boom" || !strings.Contains(stack, "TestRecoverLogsAndPanicsAgain") {
+		t.Errorf("got %v, want the panic and stack", attrs)
+	}
+}
+
+func TestRecoverAndContinue(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	func() {
+		defer RecoverAndContinue(logger)
+
+		panic("boom")
+	}()
+
+	// Nothing is logged without a panic.
+	func() {
+		defer RecoverAndContinue(logger)
+	}()
+
+	if rs := snapshot(); len(rs) != 1 || rs[0].Message != MSG_PANIC_RECOVERED {
+		t.Errorf("got %v, want one panic record", rs)
+	}
+}
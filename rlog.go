@@ -1,6 +1,18 @@
 package rlog
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // We only provide a standard interface for logging here, then the extensions in
 // one app could have a chance to use the same logging implementation.
@@ -13,9 +25,41 @@ import "sync"
 
 const (
 	KEY_DEFAULT_LOGGER = "default"
+
+	// The key of the module name attached by the loggers with module.
+	KEY_MODULE = "module"
+
+	// The key used for a value without a paired key, same as 'slog'.
+	KEY_BAD_KEY = "!BADKEY"
 )
 
-var loggers = sync.Map{} // map[string]ILogger
+var loggers = sync.Map{} // map[string]*registration
+
+// The handler registered under a name, and the attributes bound to the loggers
+// got by the name.
+type registration struct {
+	h     LogHandler
+	attrs []LogAttr
+}
+
+// The handler used for the names not registered, see 'SetFallbackHandler()'.
+var fallbackHandler atomicBox[LogHandler]
+
+// Called by 'Fatal()' after logging, replaceable in tests.
+var exitFunc = os.Exit
+
+// The clock of the records, replaceable in tests.
+var timeNow = time.Now
+
+// Where the diagnostics of the package are written, replaceable in tests.
+var stderr io.Writer = os.Stderr
+
+// Whether the panics of the handlers are recovered, see
+// 'SetRecoverHandlerPanics()'.
+var recoverPanics int32 = 1
+
+// Whether the malformed arguments panic, see 'SetStrictMode()'.
+var strictMode int32
 
 type LogLevel int8
 
@@ -24,6 +68,7 @@ const (
 	LogLevelInfo
 	LogLevelWarn
 	LogLevelError
+	LogLevelFatal
 )
 
 type LogAttr struct {
@@ -31,19 +76,197 @@ type LogAttr struct {
 	Value any
 }
 
+// Return an attribute grouping the alternating key/value pairs in 'args', its
+// value is a '[]LogAttr'. The built-in handlers render it as a nested object in
+// JSON, or with the dotted keys in text, ex: "http.method=GET".
+func Group(name string, args ...any) LogAttr {
+	return LogAttr{Key: name, Value: argsToAttrs(args)}
+}
+
+// The Valuer computes the value of an attribute lazily, it's called only when
+// the record is rendered by a handler, ex: to skip serializing a large struct
+// for the children of a multi handler which are not enabled.
+//
+// A Valuer may be called once by each handler rendering the record.
+type Valuer func() any
+
+// Return the attribute with the value computed if it's a 'Valuer'. Custom
+// handlers shall call this before rendering the value.
+func (a LogAttr) Resolve() LogAttr {
+	if v, ok := a.Value.(Valuer); ok {
+		a.Value = v()
+	}
+
+	return a
+}
+
+// The source location of a call site.
+type LogSource struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
 type LogRecord struct {
+	Time    time.Time // When the log method is called.
 	Message string
 	Attrs   []LogAttr
 	Level   LogLevel
+
+	// The call site of the log method, only captured if the handler implements
+	// 'SourceCapturer', otherwise nil.
+	Source *LogSource
+
+	// The context passed to the '*Ctx' methods, or 'context.Background()' for
+	// others. Handlers could extract the request-scoped values from it.
+	Context context.Context
+}
+
+// Return a copy of the record with the attributes deep copied, including the
+// ones in the groups, so the copy is safe to retain after 'Handle()' returns.
+func (r LogRecord) Clone() LogRecord {
+	r.Attrs = cloneAttrs(r.Attrs)
+	return r
+}
+
+func cloneAttrs(attrs []LogAttr) []LogAttr {
+	if attrs == nil {
+		return nil
+	}
+
+	cloned := make([]LogAttr, len(attrs))
+
+	for i, attr := range attrs {
+		if group, ok := attr.Value.([]LogAttr); ok {
+			attr.Value = cloneAttrs(group)
+		}
+
+		cloned[i] = attr
+	}
+
+	return cloned
+}
+
+// Convert the record to a map for the custom encoders, ex:
+//
+//	{"time": t, "level": "INFO", "msg": "hello", "k": "v", "g": {"k": "v"}}
+//
+// The groups are converted to the nested maps, except the ones with empty key
+// are inlined. The time is omitted if zero, and the 'KEY_SOURCE' is set only if
+// the source is captured.
+func (r LogRecord) ToMap() map[string]any {
+	m := make(map[string]any, len(r.Attrs)+4)
+
+	if !r.Time.IsZero() {
+		m[KEY_TIME] = r.Time
+	}
+	m[KEY_LEVEL] = r.Level.String()
+	if r.Source != nil {
+		m[KEY_SOURCE] = r.Source
+	}
+	m[KEY_MESSAGE] = r.Message
+
+	attrsToMap(m, r.Attrs)
+
+	return m
 }
 
+func attrsToMap(m map[string]any, attrs []LogAttr) {
+	for _, attr := range attrs {
+		attr = attr.Resolve()
+
+		group, ok := attr.Value.([]LogAttr)
+		if !ok {
+			m[attr.Key] = attr.Value
+			continue
+		}
+
+		if attr.Key == "" {
+			attrsToMap(m, group)
+			continue
+		}
+
+		// Merge into the group with the same key, ex: by 'With()' and the args.
+		sub, ok := m[attr.Key].(map[string]any)
+		if !ok {
+			sub = make(map[string]any, len(group))
+			m[attr.Key] = sub
+		}
+
+		attrsToMap(sub, group)
+	}
+}
+
+// The LogHandler handles the records emitted by the loggers.
+//
+// The 'Attrs' of the record passed to 'Handle()' are reused by the loggers after
+// 'Handle()' returns, the handler which retains the record must copy them
+// first with 'LogRecord.Clone()', ex: the async handler.
 type LogHandler interface {
 	Enabled(l LogLevel) bool
 	Handle(r LogRecord)
 }
 
+// The optional interface of the handlers whose level never changes, so the
+// loggers could check the level without calling 'Enabled()'. The handlers with
+// dynamic levels shall not implement it, or return false.
+type StaticLeveler interface {
+	StaticLevel() (l LogLevel, ok bool)
+}
+
 type r_logger struct {
 	handler LogHandler
+
+	// The cached 'StaticLevel()' of the handler, valid if 'static' is true.
+	static bool
+	level  LogLevel
+
+	// The minimum level set by 'WithLevel()', valid if 'leveled' is true.
+	leveled  bool
+	minLevel LogLevel
+
+	// The 'minLevel' bypasses the level of the handler, see 'ScopedLevel()'.
+	forced bool
+
+	module string        // Attached as the first attribute if not empty.
+	attrs  []LogAttr     // Prepended to the attributes of every record.
+	groups []loggerGroup // Opened by 'WithGroup()', the innermost is the last.
+}
+
+func newLogger(h LogHandler) *r_logger {
+	l := &r_logger{handler: h}
+
+	if s, ok := h.(StaticLeveler); ok {
+		l.level, l.static = s.StaticLevel()
+	}
+
+	return l
+}
+
+func (l *r_logger) Enabled(level LogLevel) bool {
+	return l.enabled(level)
+}
+
+func (l *r_logger) enabled(level LogLevel) bool {
+	if l.leveled && level < l.minLevel {
+		return false
+	}
+
+	if l.forced {
+		return true
+	}
+
+	if l.static {
+		return level >= l.level
+	}
+
+	return l.handler.Enabled(level)
+}
+
+// The group opened by 'WithGroup()', and the attributes added after it.
+type loggerGroup struct {
+	name  string
+	attrs []LogAttr
 }
 
 type ILogger interface {
@@ -51,77 +274,821 @@ type ILogger interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
+
+	// Same as the above, and the 'ctx' is carried by the record.
+	DebugCtx(ctx context.Context, msg string, args ...any)
+	InfoCtx(ctx context.Context, msg string, args ...any)
+	WarnCtx(ctx context.Context, msg string, args ...any)
+	ErrorCtx(ctx context.Context, msg string, args ...any)
+
+	// Same as the above, and the message is formatted by 'fmt.Sprintf()' with
+	// no attributes added, ex: 'Infof("user %s logged in", name)'. The message
+	// is formatted only if the level is enabled.
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
+
+	// Log at the level chosen at runtime, ex: to bridge other logging
+	// libraries. Unlike 'Fatal()' and 'Panic()', it never exits or panics.
+	Log(level LogLevel, msg string, args ...any)
+
+	// Log at 'LogLevelFatal' then call 'os.Exit(1)'.
+	Fatal(msg string, args ...any)
+
+	// Log at 'LogLevelFatal' then panic with 'msg'.
+	Panic(msg string, args ...any)
+
+	// Return a logger sharing the same handler, and the attributes in 'args'
+	// are attached to every record emitted by it. The derived logger inherits
+	// the bound attributes, groups and the level set by 'WithLevel()'.
+	With(args ...any) ILogger
+
+	// Return a logger sharing the same handler, and all the attributes added
+	// after this, by 'With()' or the log methods, are nested in the group.
+	WithGroup(name string) ILogger
+
+	// Return a logger sharing the same handler, and the records with level less
+	// than 'min' are dropped, even if the handler is enabled for them. The
+	// 'min' replaces the one set by the previous 'WithLevel()'.
+	WithLevel(min LogLevel) ILogger
+
+	// Return the module name of the logger got by 'GetLoggerWithModule()', or
+	// empty for others. The derived loggers keep the name.
+	Name() string
+
+	// Report whether the records at 'level' are emitted, ex: to skip building
+	// the expensive arguments. It honours both the handler and 'WithLevel()'.
+	Enabled(level LogLevel) bool
 }
 
-func (l *r_logger) doLog(msg string, level LogLevel, args ...any) {
-	size := len(args) / 2
-	attrs := make([]LogAttr, size)
+// Convert the key of one attribute to string, the non-string key will be
+// formatted with '%v' rather than panicking, unless in the strict mode.
+func keyOf(k any) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+
+	if atomic.LoadInt32(&strictMode) != 0 {
+		panic(fmt.Sprintf("rlog: non-string key %v (%T)", k, k))
+	}
+
+	return fmt.Sprintf("%v", k)
+}
+
+// Convert the alternating key/value pairs to attributes, and the 'LogAttr',
+// '[]LogAttr' or 'map[string]any' in 'args' is used directly without a paired
+// key. The entries of the map are converted in the order of the keys.
+func argsToAttrs(args []any) []LogAttr {
+	return appendArgs(make([]LogAttr, 0, (len(args)+1)/2), args)
+}
+
+// Same as 'argsToAttrs()', but append the attributes to 'dst'.
+func appendArgs(dst []LogAttr, args []any) []LogAttr {
+	for i := 0; i < len(args); {
+		switch a := args[i].(type) {
+		case LogAttr:
+			a.Key = renameKey(a.Key)
+			dst = append(dst, a)
+			i++
+			continue
+		case []LogAttr:
+			for _, attr := range a {
+				attr.Key = renameKey(attr.Key)
+				dst = append(dst, attr)
+			}
+			i++
+			continue
+		case map[string]any:
+			dst = appendMap(dst, a)
+			i++
+			continue
+		}
+
+		// The last argument has no paired key, keep the value anyway.
+		if i+1 == len(args) {
+			if atomic.LoadInt32(&strictMode) != 0 {
+				panic(fmt.Sprintf("rlog: value %v has no paired key", args[i]))
+			}
 
-	for i := 0; i < len(args); i += 2 {
-		attrs[i/2] = LogAttr{
-			Key:   args[i].(string),
+			dst = append(dst, LogAttr{
+				Key:   KEY_BAD_KEY,
+				Value: args[i],
+			})
+			break
+		}
+
+		dst = append(dst, LogAttr{
+			Key:   renameKey(keyOf(args[i])),
 			Value: args[i+1],
+		})
+		i += 2
+	}
+
+	return dst
+}
+
+func appendMap(dst []LogAttr, m map[string]any) []LogAttr {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		dst = append(dst, LogAttr{Key: renameKey(k), Value: m[k]})
+	}
+
+	return dst
+}
+
+// The attribute slices of the records are reused, see 'LogHandler'.
+var attrsPool = sync.Pool{
+	New: func() any {
+		attrs := make([]LogAttr, 0, 8)
+		return &attrs
+	},
+}
+
+// Not to hold a large slice in the pool forever.
+const maxPooledAttrs = 64
+
+func (l *r_logger) doLog(
+	ctx context.Context,
+	msg string,
+	level LogLevel,
+	args ...any,
+) {
+	countLevel(level)
+
+	var extract ContextExtractor
+	if ctx == nil {
+		ctx = context.Background()
+	} else {
+		extract = loadContextExtractor()
+	}
+
+	// The record has no attributes, ex: 'Info("started")', so no slice is
+	// taken from the pool.
+	if len(args) == 0 && l.module == "" && len(l.attrs) == 0 &&
+		len(l.groups) == 0 && extract == nil {
+		l.emit(ctx, msg, level, nil)
+		return
+	}
+
+	p := attrsPool.Get().(*[]LogAttr)
+	attrs := l.appendBoundAttrs((*p)[:0])
+
+	if len(l.groups) == 0 {
+		attrs = appendArgs(attrs, args)
+	} else {
+		attrs = append(attrs, l.nestInGroups(argsToAttrs(args))...)
+	}
+
+	if extract != nil {
+		attrs = append(attrs, extract(ctx)...)
+	}
+
+	attrs = applyDebugAttrs(attrs, level)
+
+	l.emit(ctx, msg, level, attrs)
+
+	if cap(attrs) <= maxPooledAttrs {
+		// Not to retain the values by the pooled slice.
+		for i := range attrs {
+			attrs[i] = LogAttr{}
 		}
+
+		*p = attrs[:0]
+		attrsPool.Put(p)
 	}
+}
+
+// Create the record and pass it to the handler.
+func (l *r_logger) emit(
+	ctx context.Context,
+	msg string,
+	level LogLevel,
+	attrs []LogAttr,
+) {
 
-	l.handler.Handle(LogRecord{
+	r := LogRecord{
+		Time:    timeNow(),
 		Message: msg,
 		Attrs:   attrs,
 		Level:   level,
-	})
+		Context: ctx,
+	}
+
+	if captureSource(l.handler) {
+		// Skip 'emit()', 'doLog()', 'log()' and the log method, ex: 'Info()'.
+		r.Source = callerSource(4)
+	}
+
+	o := loadObserver()
+	if o == nil {
+		l.handle(r)
+		return
+	}
+
+	o.ObserveRecord(r)
+	start := time.Now()
+	l.handle(r)
+	o.ObserveHandleLatency(time.Since(start))
 }
 
-func (l *r_logger) Debug(msg string, args ...any) {
-	if l.handler.Enabled(LogLevelDebug) {
-		l.doLog(msg, LogLevelDebug, args...)
+// Call the handler, and recover its panic if enabled, so logging never breaks
+// the caller.
+func (l *r_logger) handle(r LogRecord) {
+	if atomic.LoadInt32(&recoverPanics) != 0 {
+		defer func() {
+			if err := recover(); err != nil {
+				reportPanic(err)
+			}
+		}()
 	}
+
+	l.handler.Handle(r)
 }
 
-func (l *r_logger) Info(msg string, args ...any) {
-	if l.handler.Enabled(LogLevelInfo) {
-		l.doLog(msg, LogLevelInfo, args...)
+// Flush the handler if it implements 'Flusher', the error and the panic are
+// written to the stderr.
+func (l *r_logger) flush() {
+	defer func() {
+		if err := recover(); err != nil {
+			reportPanic(err)
+		}
+	}()
+
+	if err := flushHandler(l.handler); err != nil {
+		reportError(err)
 	}
 }
 
-func (l *r_logger) Warn(msg string, args ...any) {
-	if l.handler.Enabled(LogLevelWarn) {
-		l.doLog(msg, LogLevelWarn, args...)
+// Write the recovered panic of a handler to the stderr.
+func reportPanic(v any) {
+	fmt.Fprintf(stderr, "rlog: handler panicked: %v\n", v)
+}
+
+// Set whether the panics of the handlers are recovered, enabled by default.
+//
+// The recovered panics are written to the stderr, disable this to panic in the
+// caller instead, ex: to catch the buggy handlers in development.
+func SetRecoverHandlerPanics(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
 	}
+
+	atomic.StoreInt32(&recoverPanics, v)
 }
 
-func (l *r_logger) Error(msg string, args ...any) {
-	if l.handler.Enabled(LogLevelError) {
-		l.doLog(msg, LogLevelError, args...)
+// Set whether the malformed arguments of the log methods and 'With()' panic,
+// disabled by default.
+//
+// The value without paired key is kept with 'KEY_BAD_KEY' and the non-string
+// key is formatted, enable this to panic in the caller instead, ex: to catch
+// the mistakes in development.
+func SetStrictMode(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&strictMode, v)
+}
+
+// Return the source location of the caller, 'skip' is the number of frames to
+// skip above the caller of this. The frames of the writer adapter and the
+// standard logger are skipped too, so the call sites of them are reported.
+func callerSource(skip int) *LogSource {
+	var pcs [8]uintptr
+
+	// Skip 'runtime.Callers()' and this.
+	n := runtime.Callers(skip+2, pcs[:])
+	if n == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !more || !isBridgeFrame(frame.Function) {
+			return &LogSource{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			}
+		}
+	}
+}
+
+func sourceOf(pc uintptr) *LogSource {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	return &LogSource{
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+	}
+}
+
+// Return the attributes of the record, the module and the bound attributes
+// first, then the 'attrs' nested in the opened groups.
+func (l *r_logger) collectAttrs(attrs []LogAttr) []LogAttr {
+	attrs = l.nestInGroups(attrs)
+
+	if l.module == "" && len(l.attrs) == 0 {
+		return attrs
+	}
+
+	dst := make([]LogAttr, 0, len(attrs)+len(l.attrs)+1)
+	dst = l.appendBoundAttrs(dst)
+
+	return append(dst, attrs...)
+}
+
+// Append the module and the attributes bound by 'With()' outside any group.
+func (l *r_logger) appendBoundAttrs(dst []LogAttr) []LogAttr {
+	if l.module != "" {
+		dst = append(dst, LogAttr{Key: KEY_MODULE, Value: l.module})
+	}
+
+	return append(dst, l.attrs...)
+}
+
+// Nest the 'attrs' in the opened groups, with the attributes bound to each
+// group. The empty groups are omitted.
+func (l *r_logger) nestInGroups(attrs []LogAttr) []LogAttr {
+	for i := len(l.groups) - 1; i >= 0; i-- {
+		g := l.groups[i]
+
+		if len(g.attrs)+len(attrs) == 0 {
+			continue
+		}
+
+		attrs = []LogAttr{{Key: g.name, Value: concatAttrs(g.attrs, attrs)}}
+	}
+
+	return attrs
+}
+
+// Return a new slice, so the 'a' held by loggers is never modified.
+func concatAttrs(a, b []LogAttr) []LogAttr {
+	attrs := make([]LogAttr, 0, len(a)+len(b))
+	attrs = append(attrs, a...)
+	attrs = append(attrs, b...)
+
+	return attrs
+}
+
+func (l *r_logger) With(args ...any) ILogger {
+	if len(args) == 0 {
+		return l
+	}
+
+	c := *l
+	attrs := argsToAttrs(args)
+
+	if n := len(l.groups); n == 0 {
+		c.attrs = concatAttrs(l.attrs, attrs)
+	} else {
+		c.groups = make([]loggerGroup, n)
+		copy(c.groups, l.groups)
+		c.groups[n-1].attrs = concatAttrs(l.groups[n-1].attrs, attrs)
+	}
+
+	return &c
+}
+
+func (l *r_logger) WithGroup(name string) ILogger {
+	if name == "" {
+		return l
+	}
+
+	c := *l
+	c.groups = make([]loggerGroup, len(l.groups), len(l.groups)+1)
+	copy(c.groups, l.groups)
+	c.groups = append(c.groups, loggerGroup{name: name})
+
+	return &c
+}
+
+func (l *r_logger) WithLevel(min LogLevel) ILogger {
+	c := *l
+	c.leveled = true
+	c.minLevel = min
+	c.forced = false
+
+	return &c
+}
+
+// Run 'f' with a logger derived from 'l' which emits the records with level not
+// less than 'level', even if the handler is not enabled for them, ex: to debug
+// a code path while the handler stays at info:
+//
+//	rlog.ScopedLevel(logger, rlog.LogLevelDebug, func(l rlog.ILogger) {
+//		l.Debug("emitted")
+//	})
+//
+// Only the logger passed to 'f' and the ones derived from it are affected, so
+// the other callers sharing the handler are not. The wrapper handlers which
+// filter the records by level in 'Handle()' may still drop them.
+func ScopedLevel(l ILogger, level LogLevel, f func(ILogger)) {
+	rl, ok := l.(*r_logger)
+	if !ok {
+		f(l)
+		return
+	}
+
+	c := *rl
+	c.leveled = true
+	c.minLevel = level
+	c.forced = true
+
+	f(&c)
+}
+
+func (l *r_logger) Name() string {
+	return l.module
+}
+
+// The 'ctx' is nil if the log method has no context.
+func (l *r_logger) log(
+	ctx context.Context,
+	level LogLevel,
+	msg string,
+	args ...any,
+) {
+	if l.enabled(level) {
+		l.doLog(ctx, msg, level, args...)
 	}
 }
 
+// Same as 'log()', and the message is formatted with 'args'.
+func (l *r_logger) logf(level LogLevel, format string, args ...any) {
+	if l.enabled(level) {
+		l.doLog(nil, fmt.Sprintf(format, args...), level)
+	}
+}
+
+func (l *r_logger) Debug(msg string, args ...any) {
+	l.log(nil, LogLevelDebug, msg, args...)
+}
+
+func (l *r_logger) Info(msg string, args ...any) {
+	l.log(nil, LogLevelInfo, msg, args...)
+}
+
+func (l *r_logger) Warn(msg string, args ...any) {
+	l.log(nil, LogLevelWarn, msg, args...)
+}
+
+func (l *r_logger) Error(msg string, args ...any) {
+	l.log(nil, LogLevelError, msg, args...)
+}
+
+func (l *r_logger) Log(level LogLevel, msg string, args ...any) {
+	l.log(nil, level, msg, args...)
+}
+
+func (l *r_logger) Debugf(format string, args ...any) {
+	l.logf(LogLevelDebug, format, args...)
+}
+
+func (l *r_logger) Infof(format string, args ...any) {
+	l.logf(LogLevelInfo, format, args...)
+}
+
+func (l *r_logger) Warnf(format string, args ...any) {
+	l.logf(LogLevelWarn, format, args...)
+}
+
+func (l *r_logger) Errorf(format string, args ...any) {
+	l.logf(LogLevelError, format, args...)
+}
+
+func (l *r_logger) Fatal(msg string, args ...any) {
+	l.log(nil, LogLevelFatal, msg, args...)
+
+	// The deferred calls never run after the exit, so the buffered records of
+	// the handler are written first.
+	l.flush()
+	exitFunc(1)
+}
+
+func (l *r_logger) Panic(msg string, args ...any) {
+	l.log(nil, LogLevelFatal, msg, args...)
+	panic(msg)
+}
+
+func (l *r_logger) DebugCtx(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, LogLevelDebug, msg, args...)
+}
+
+func (l *r_logger) InfoCtx(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, LogLevelInfo, msg, args...)
+}
+
+func (l *r_logger) WarnCtx(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, LogLevelWarn, msg, args...)
+}
+
+func (l *r_logger) ErrorCtx(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, LogLevelError, msg, args...)
+}
+
 // Register the handler if not absent.
 //
 // Set the logging implementation with this function, this function must be
 // called before 'GetDefaultLogger()' or 'GetLogger()'. In general, this
 // function shall be called in the 'main()' function, before starting the rte
 // app.
+//
+// The nil handler is never registered, false is returned.
 func RegisterLogHandler(name string, h LogHandler) (ok bool) {
-	_, loaded := loggers.LoadOrStore(name, h)
+	return RegisterLogHandlerWithAttrs(name, h)
+}
+
+var ErrNilHandler = errors.New("rlog: nil handler")
+
+// The error returned by 'RegisterLogHandlerE()' if the name is registered.
+type ErrHandlerExists struct {
+	Name string
+}
+
+func (e ErrHandlerExists) Error() string {
+	return fmt.Sprintf("rlog: handler %q already registered", e.Name)
+}
+
+// Same as 'RegisterLogHandler()', but return 'ErrNilHandler' or
+// 'ErrHandlerExists' rather than false, ex: to fail the startup loudly on the
+// misconfiguration.
+func RegisterLogHandlerE(name string, h LogHandler) error {
+	if h == nil {
+		return ErrNilHandler
+	}
+
+	if !RegisterLogHandler(name, h) {
+		return ErrHandlerExists{Name: name}
+	}
+
+	return nil
+}
+
+// Same as 'RegisterLogHandler()', and the 'attrs' are prepended to the records
+// of every logger got by the name, as if bound by 'With()'. Unlike the
+// 'NewWithFields()', the attributes are scoped to the name even if the handler
+// is shared by other names.
+func RegisterLogHandlerWithAttrs(
+	name string,
+	h LogHandler,
+	attrs ...LogAttr,
+) (ok bool) {
+	if h == nil {
+		return false
+	}
+
+	reg := &registration{h: h, attrs: concatAttrs(attrs, nil)}
+	_, loaded := loggers.LoadOrStore(name, reg)
 
 	if loaded {
 		return false
 	}
 
+	replayPending(name, reg)
+
+	return true
+}
+
+// Remove the handler registered under the name, 'ok' reports whether it was
+// registered.
+//
+// The loggers got before hold the removed handler and keep working, only the
+// loggers got after this see the change.
+func UnregisterLogHandler(name string) (ok bool) {
+	_, ok = loggers.LoadAndDelete(name)
+	return ok
+}
+
+// Register the handler, replacing the existing one if any. The nil handler is
+// rejected, and 'ok' is false. The attributes bound by
+// 'RegisterLogHandlerWithAttrs()' are kept for the new handler.
+//
+// Same as 'UnregisterLogHandler()', the loggers got before still hold the old
+// handler, get them again to use the new one.
+func ReplaceLogHandler(name string, h LogHandler) (ok bool) {
+	if h == nil {
+		return false
+	}
+
+	swapRegistration(name, h)
+
 	return true
 }
 
+// Register 'h' with the attributes of the existing registration, and return
+// the existing one, nil if absent.
+func swapRegistration(name string, h LogHandler) (old *registration) {
+	reg := &registration{h: h}
+
+	if v, ok := loggers.Load(name); ok {
+		old = v.(*registration)
+		reg.attrs = old.attrs
+	}
+
+	loggers.Store(name, reg)
+	replayPending(name, reg)
+
+	return old
+}
+
+// Same as 'ReplaceLogHandler()', and the old handler is flushed after the swap
+// if it implements 'Flusher', ex: so the records buffered by an async handler
+// reach the old sink rather than getting lost. The error of the flush is
+// returned, and the new handler is registered anyway.
+//
+// The old handler is not closed, since the loggers got before may still use
+// it. Close it once they are gone.
+func SwapHandler(name string, new LogHandler) error {
+	if new == nil {
+		return ErrNilHandler
+	}
+
+	old := swapRegistration(name, new)
+	if old == nil {
+		return nil
+	}
+
+	return flushHandler(old.h)
+}
+
+// Return the sorted names of the registered handlers.
+func ListLoggers() []string {
+	names := []string{}
+
+	loggers.Range(func(k, _ any) bool {
+		names = append(names, k.(string))
+		return true
+	})
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Capture the registered handlers and the fallback handler, and the returned
+// function restores them, ex: to isolate the tests registering the handlers:
+//
+//	defer rlog.SnapshotRegistry()()
+//
+// The handlers registered after the snapshot are removed by the restore, but
+// not closed. And the records buffered for the names got after the snapshot
+// are dropped, see 'SetPendingBufferSize()'.
+func SnapshotRegistry() (restore func()) {
+	saved := map[any]any{}
+
+	loggers.Range(func(k, v any) bool {
+		saved[k] = v
+		return true
+	})
+
+	pendingsMu.Lock()
+	savedPendings := make(map[string]*pendingHandler, len(pendings))
+	for k, v := range pendings {
+		savedPendings[k] = v
+	}
+	pendingsMu.Unlock()
+
+	fallback := fallbackHandler.Load()
+
+	return func() {
+		loggers.Range(func(k, _ any) bool {
+			if _, ok := saved[k]; !ok {
+				loggers.Delete(k)
+			}
+			return true
+		})
+
+		for k, v := range saved {
+			loggers.Store(k, v)
+		}
+
+		pendingsMu.Lock()
+		for k, v := range pendings {
+			if savedPendings[k] != v {
+				delete(pendings, k)
+			}
+		}
+		pendingsMu.Unlock()
+
+		fallbackHandler.Store(fallback)
+	}
+}
+
+// Flush and close all the registered handlers implementing 'Flusher' or
+// 'Closer', the errors of them are aggregated. The handler registered under
+// several names is closed once. In general, this function shall be called in
+// the shutdown hook of the app, the records may be dropped after this.
+func CloseAll() error {
+	var errs multiError
+	seen := map[LogHandler]bool{}
+
+	loggers.Range(func(_, v any) bool {
+		h := v.(*registration).h
+
+		// The uncomparable handlers panic as the map keys.
+		if reflect.TypeOf(h).Comparable() {
+			if seen[h] {
+				return true
+			}
+			seen[h] = true
+		}
+
+		if err := flushHandler(h); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := closeHandler(h); err != nil {
+			errs = append(errs, err)
+		}
+
+		return true
+	})
+
+	return errs.orNil()
+}
+
+// Register the handler of the default logger if not absent, same as
+// 'RegisterLogHandler()'.
+func SetDefaultLogHandler(h LogHandler) (ok bool) {
+	return RegisterLogHandler(KEY_DEFAULT_LOGGER, h)
+}
+
+// Remove the handler of the default logger, same as 'UnregisterLogHandler()'.
+func UnsetDefaultLogHandler() {
+	UnregisterLogHandler(KEY_DEFAULT_LOGGER)
+}
+
 func GetDefaultLogger() ILogger {
 	return GetLogger(KEY_DEFAULT_LOGGER)
 }
 
+// Get the logger backed by the handler registered under the name.
+//
+// If the handler is absent, the logger backed by the fallback handler is
+// returned, see 'SetFallbackHandler()'. Or a logger which buffers the records
+// until the handler is registered under the name, then replays them to it, so
+// the records logged in the early init are kept, see 'SetPendingBufferSize()'.
+// Or a no-op logger which drops all records if the buffering is disabled, so
+// the caller never needs to check nil before logging. Use 'LookupLogger()' if
+// the caller cares about whether the handler is registered.
 func GetLogger(handler string) ILogger {
-	logger, ok := loggers.Load(handler)
+	logger, _ := LookupLogger(handler)
+	return logger
+}
+
+// Same as 'GetLogger()', and the returned logger attaches 'module' to every
+// record with 'KEY_MODULE', ex: "module=db".
+func GetLoggerWithModule(handler, module string) ILogger {
+	logger, _ := LookupLogger(handler)
+
+	// Either registered, buffered or backed by the fallback handler.
+	if l, ok := logger.(*r_logger); ok {
+		l.module = module
+	}
+
+	return logger
+}
+
+// Same as 'GetLogger()', but 'ok' reports whether the handler is registered.
+func LookupLogger(handler string) (logger ILogger, ok bool) {
+	v, ok := loggers.Load(handler)
 
 	if ok {
-		h := logger.(LogHandler)
-		return &r_logger{handler: h}
-	} else {
-		return nil
+		reg := v.(*registration)
+
+		l := newLogger(reg.h)
+		l.attrs = reg.attrs
+
+		return l, true
+	}
+
+	if h := fallbackHandler.Load(); h != nil {
+		return newLogger(h), false
 	}
+
+	if p := loadPendingHandler(handler); p != nil {
+		return newLogger(p), false
+	}
+
+	return nopLogger{}, false
+}
+
+// Set the handler used by 'GetLogger()' for the names not registered, ex: a
+// catch-all sink in case the app forgets to register some names. Pass nil to
+// remove it.
+func SetFallbackHandler(h LogHandler) {
+	fallbackHandler.Store(h)
 }
@@ -0,0 +1,960 @@
+package rlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Register a memory handler under the test name, the registry is restored
+// once the test ends.
+func newTestLogger(t *testing.T, level Leveler) (ILogger, func() []LogRecord) {
+	t.Helper()
+	t.Cleanup(SnapshotRegistry())
+
+	h, snapshot := NewMemoryHandler(level)
+	if !RegisterLogHandler(t.Name(), h) {
+		t.Fatalf("register %q failed", t.Name())
+	}
+
+	return GetLogger(t.Name()), snapshot
+}
+
+// Capture the diagnostics written to the stderr until the test ends.
+func captureStderr(t *testing.T) *syncBuffer {
+	t.Helper()
+
+	buf := &syncBuffer{}
+	old := stderr
+	stderr = buf
+	t.Cleanup(func() { stderr = old })
+
+	return buf
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestZeroArgsRecordHasNoAttrs(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	logger.Info("started")
+
+	rs := snapshot()
+	if len(rs) != 1 {
+		t.Fatalf("got %d records, want 1", len(rs))
+	}
+	if rs[0].Message != "started" || len(rs[0].Attrs) != 0 {
+		t.Errorf("got %q with %v, want no attrs", rs[0].Message, rs[0].Attrs)
+	}
+}
+
+func TestZeroArgsNeverAllocates(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	RegisterLogHandler(t.Name(), NewDiscardHandler())
+	logger := GetLogger(t.Name())
+
+	if n := testing.AllocsPerRun(100, func() { logger.Info("started") }); n != 0 {
+		t.Errorf("got %v allocs, want 0", n)
+	}
+}
+
+func TestZeroArgsKeepsGroupAttrs(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	logger.WithGroup("db").With("k", "v").Info("x")
+
+	rs := snapshot()
+	if len(rs) != 1 || len(rs[0].Attrs) != 1 {
+		t.Fatalf("got %v, want one group", rs)
+	}
+
+	group, ok := rs[0].Attrs[0].Value.([]LogAttr)
+	if rs[0].Attrs[0].Key != "db" || !ok || len(group) != 1 ||
+		group[0] != (LogAttr{Key: "k", Value: "v"}) {
+		t.Errorf("got %v, want db.k=v", rs[0].Attrs)
+	}
+}
+
+func BenchmarkInfoZeroArgs(b *testing.B) {
+	defer SnapshotRegistry()()
+
+	RegisterLogHandler(b.Name(), NewDiscardHandler())
+	logger := GetLogger(b.Name())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("started")
+	}
+}
+
+func BenchmarkInfoWithArgs(b *testing.B) {
+	defer SnapshotRegistry()()
+
+	RegisterLogHandler(b.Name(), NewDiscardHandler())
+	logger := GetLogger(b.Name())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("request", "method", "GET", "status", 200)
+	}
+}
+
+func TestReplaceKeepsRegisteredAttrs(t *testing.T) {
+	defer SnapshotRegistry()()
+
+	old, _ := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandlerWithAttrs(t.Name(), old, Str("app", "demo"))
+
+	for _, swap := range []func(h LogHandler){
+		func(h LogHandler) { ReplaceLogHandler(t.Name(), h) },
+		func(h LogHandler) { SwapHandler(t.Name(), h) },
+	} {
+		h, snapshot := NewMemoryHandler(LogLevelInfo)
+		swap(h)
+
+		GetLogger(t.Name()).Info("hi")
+
+		rs := snapshot()
+		if len(rs) != 1 || len(rs[0].Attrs) != 1 ||
+			rs[0].Attrs[0] != (LogAttr{Key: "app", Value: "demo"}) {
+			t.Errorf("got %v, want app=demo", rs)
+		}
+	}
+}
+
+func TestFatalFlushesBeforeExit(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	h, closeFn := NewBatchHandler(mem, 10, 0)
+	defer closeFn()
+	RegisterLogHandler(t.Name(), h)
+
+	old := exitFunc
+	defer func() { exitFunc = old }()
+
+	var flushed int
+	exitFunc = func(code int) { flushed = len(snapshot()) }
+
+	GetLogger(t.Name()).Fatal("bye")
+
+	if flushed != 1 {
+		t.Errorf("got %d records before the exit, want 1", flushed)
+	}
+}
+
+func TestArgsHaveNoTrailingEmptyAttr(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	logger.Info("a", "k", "v")
+	logger.Info("b", "k1", 1, "k2", 2)
+
+	rs := snapshot()
+	if len(rs) != 2 {
+		t.Fatalf("got %d records, want 2", len(rs))
+	}
+	for i, want := range []int{1, 2} {
+		if len(rs[i].Attrs) != want {
+			t.Errorf("got attrs %v, want %d", rs[i].Attrs, want)
+		}
+		for _, a := range rs[i].Attrs {
+			if a.Key == "" {
+				t.Errorf("got empty attr in %v", rs[i].Attrs)
+			}
+		}
+	}
+}
+
+func TestOddArgsKeepTheValue(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	logger.Info("a", "k", "v", "orphan")
+
+	rs := snapshot()
+	if len(rs) != 1 || len(rs[0].Attrs) != 2 ||
+		rs[0].Attrs[1] != (LogAttr{Key: KEY_BAD_KEY, Value: "orphan"}) {
+		t.Errorf("got %v, want the orphan with %s", rs, KEY_BAD_KEY)
+	}
+}
+
+func TestOddArgsPanicInStrictMode(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelInfo)
+
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("got no panic")
+		}
+	}()
+
+	logger.Info("a", "orphan")
+}
+
+func TestNonStringKeyIsFormatted(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	logger.Info("a", 42, "v", nil, 1)
+
+	rs := snapshot()
+	if len(rs) != 1 || len(rs[0].Attrs) != 2 ||
+		rs[0].Attrs[0] != (LogAttr{Key: "42", Value: "v"}) ||
+		rs[0].Attrs[1] != (LogAttr{Key: "<nil>", Value: 1}) {
+		t.Errorf("got %v, want the formatted keys", rs)
+	}
+}
+
+func TestNonStringKeyPanicsInStrictMode(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	for name, fn := range map[string]func(){
+		"Info": func() { logger.Info("a", 42, "v") },
+		"With": func() { logger.With(42, "v") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s got no panic", name)
+				}
+			}()
+
+			fn()
+		}()
+	}
+
+	// Lenient again once disabled.
+	SetStrictMode(false)
+	logger.Info("b", 42, "v")
+
+	if rs := snapshot(); len(rs) != 1 || rs[0].Message != "b" {
+		t.Errorf("got %v, want b only", rs)
+	}
+}
+
+func TestWithBindsAttrs(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	child := logger.With("a", 1)
+	child.With("b", 2).Info("x", "c", 3)
+	child.Info("y")
+	logger.Info("z")
+
+	rs := snapshot()
+	if len(rs) != 3 {
+		t.Fatalf("got %d records, want 3", len(rs))
+	}
+	for i, want := range []string{"a b c", "a", ""} {
+		keys := []string{}
+		for _, a := range rs[i].Attrs {
+			keys = append(keys, a.Key)
+		}
+		if got := strings.Join(keys, " "); got != want {
+			t.Errorf("got keys %q of %q, want %q", got, rs[i].Message, want)
+		}
+	}
+}
+
+func TestFatalAndPanic(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	old := exitFunc
+	defer func() { exitFunc = old }()
+
+	code := -1
+	exitFunc = func(c int) { code = c }
+
+	logger.Fatal("bye", "k", 1)
+	if code != 1 {
+		t.Errorf("got exit code %d, want 1", code)
+	}
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("got panic %v, want boom", p)
+			}
+		}()
+
+		logger.Panic("boom")
+	}()
+
+	rs := snapshot()
+	if len(rs) != 2 || rs[0].Level != LogLevelFatal || rs[1].Level != LogLevelFatal {
+		t.Errorf("got %v, want 2 fatal records", rs)
+	}
+}
+
+func TestUnregisterAndReplace(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	old, olds := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandler(t.Name(), old)
+	before := GetLogger(t.Name())
+
+	h, hs := NewMemoryHandler(LogLevelInfo)
+	if !ReplaceLogHandler(t.Name(), h) {
+		t.Fatal("got replace failed")
+	}
+	if ReplaceLogHandler(t.Name(), nil) {
+		t.Error("got nil handler replaced")
+	}
+
+	before.Info("old")
+	GetLogger(t.Name()).Info("new")
+
+	if rs := olds(); len(rs) != 1 || rs[0].Message != "old" {
+		t.Errorf("got old records %v", rs)
+	}
+	if rs := hs(); len(rs) != 1 || rs[0].Message != "new" {
+		t.Errorf("got new records %v", rs)
+	}
+
+	if !UnregisterLogHandler(t.Name()) || UnregisterLogHandler(t.Name()) {
+		t.Error("got the wrong unregistration")
+	}
+	if _, ok := LookupLogger(t.Name()); ok {
+		t.Error("got registered after the unregistration")
+	}
+}
+
+func TestSwapHandlerFlushesTheOld(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	async, closeFn := NewAsyncHandler(mem, 128)
+	defer closeFn()
+
+	RegisterLogHandler(t.Name(), async)
+	logger := GetLogger(t.Name())
+	for i := 0; i < 100; i++ {
+		logger.Info("old")
+	}
+
+	next, nextRecords := NewMemoryHandler(LogLevelInfo)
+	if err := SwapHandler(t.Name(), next); err != nil {
+		t.Fatal(err)
+	}
+
+	// Flushed before the swap returns.
+	if n := len(snapshot()); n != 100 {
+		t.Errorf("got %d records in the old sink, want 100", n)
+	}
+
+	GetLogger(t.Name()).Info("new")
+	if rs := nextRecords(); len(rs) != 1 || rs[0].Message != "new" {
+		t.Errorf("got %v, want new", rs)
+	}
+
+	if err := SwapHandler(t.Name(), nil); err != ErrNilHandler {
+		t.Errorf("got %v, want ErrNilHandler", err)
+	}
+}
+
+func TestSetDefaultLogHandler(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+	UnsetDefaultLogHandler()
+
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+	if !SetDefaultLogHandler(h) || SetDefaultLogHandler(h) {
+		t.Fatal("got the wrong registration")
+	}
+
+	GetDefaultLogger().Info("a")
+	if rs := snapshot(); len(rs) != 1 {
+		t.Errorf("got %d records, want 1", len(rs))
+	}
+
+	UnsetDefaultLogHandler()
+	if _, ok := LookupLogger(KEY_DEFAULT_LOGGER); ok {
+		t.Error("got registered after unset")
+	}
+}
+
+func TestWithGroupNestsAttrs(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	buf := bytes.Buffer{}
+	RegisterLogHandler(t.Name(), NewLogfmtHandler(&buf, LogLevelInfo))
+
+	logger := GetLogger(t.Name()).With("a", 1).WithGroup("req").With("id", 7)
+	logger.WithGroup("db").Info("q", "ms", 3)
+	logger.WithGroup("").Info("r")
+
+	want := "level=INFO msg=q a=1 req.id=7 req.db.ms=3\n" +
+		"level=INFO msg=r a=1 req.id=7\n"
+	if got := stripTime(buf.String()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// Remove the leading time fields of the lines.
+func stripTime(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "time=") {
+			_, lines[i], _ = strings.Cut(line, " ")
+		}
+	}
+
+	return strings.Join(lines, "")
+}
+
+func TestModuleIsAttached(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+	RegisterLogHandlerWithAttrs(t.Name(), h, Str("app", "x"))
+
+	logger := GetLoggerWithModule(t.Name(), "db")
+	logger.With("k", "v").Info("a")
+	logger.Info("b")
+	GetLogger(t.Name()).Info("c")
+
+	rs := snapshot()
+	if len(rs) != 3 {
+		t.Fatalf("got %d records, want 3", len(rs))
+	}
+	for i, want := range [][]string{{"module", "app", "k"}, {"module", "app"}, {"app"}} {
+		keys := []string{}
+		for _, a := range rs[i].Attrs {
+			keys = append(keys, a.Key)
+		}
+		if strings.Join(keys, " ") != strings.Join(want, " ") {
+			t.Errorf("got keys %v of %q, want %v", keys, rs[i].Message, want)
+		}
+	}
+	if logger.Name() != "db" {
+		t.Errorf("got name %q, want db", logger.Name())
+	}
+}
+
+func TestSourceIsCapturedOnDemand(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	h := &sourceRecorder{}
+	RegisterLogHandler(t.Name(), h)
+
+	_, _, line, _ := runtime.Caller(0)
+	GetLogger(t.Name()).Info("a")
+	GetLogger(t.Name()).With("k", 1).Infof("b")
+	GetLogger(t.Name()).WarnCtx(context.Background(), "c")
+
+	if len(h.sources) != 3 {
+		t.Fatalf("got %d records, want 3", len(h.sources))
+	}
+	for _, s := range h.sources {
+		if s == nil || filepath.Base(s.File) != "rlog_test.go" ||
+			s.Line <= line || s.Line > line+3 ||
+			!strings.HasSuffix(s.Function, ".TestSourceIsCapturedOnDemand") {
+			t.Errorf("got source %+v", s)
+		}
+	}
+
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	newLogger(mem).Info("d")
+	if rs := snapshot(); rs[0].Source != nil {
+		t.Errorf("got source %+v, want nil", rs[0].Source)
+	}
+}
+
+func TestRecordTimeIsCapturedAtEmit(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	old := timeNow
+	defer func() { timeNow = old }()
+	timeNow = func() time.Time { return at }
+
+	logger.Info("a")
+
+	if rs := snapshot(); len(rs) != 1 || !rs[0].Time.Equal(at) {
+		t.Errorf("got %v, want the time %v", rs, at)
+	}
+}
+
+// The handler keeps the attributes without cloning them, against the contract
+// of 'LogHandler', to observe the reuse.
+type retainingHandler struct {
+	attrs [][]LogAttr
+}
+
+func (h *retainingHandler) Enabled(LogLevel) bool { return true }
+
+func (h *retainingHandler) Handle(r LogRecord) { h.attrs = append(h.attrs, r.Attrs) }
+
+func TestPooledAttrsAreClearedAndReused(t *testing.T) {
+	h := &retainingHandler{}
+	logger := newLogger(h).With("bound", 1)
+
+	logger.Info("a", "k", "v")
+
+	// The values are not retained by the pooled slice.
+	for _, a := range h.attrs[0] {
+		if a != (LogAttr{}) {
+			t.Errorf("got retained attr %v", a)
+		}
+	}
+
+	mem, snapshot := NewMemoryHandler(LogLevelInfo)
+	logger = newLogger(mem).With("bound", 1)
+	logger.Info("a", "k", "v1")
+	logger.Info("b", "k", "v2")
+
+	rs := snapshot()
+	if rs[0].Attrs[1].Value != "v1" || rs[1].Attrs[1].Value != "v2" {
+		t.Errorf("got %v, want the cloned attrs kept", rs)
+	}
+}
+
+func BenchmarkInfoWithBoundAttrs(b *testing.B) {
+	logger := newLogger(NewDiscardHandler()).With("app", "demo", "region", "eu")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("request", "method", "GET", "status", 200)
+	}
+}
+
+func TestHandlerPanicNeverBreaksCaller(t *testing.T) {
+	buf := captureStderr(t)
+	logger := newLogger(panicHandler{})
+
+	logger.Info("a")
+	if !strings.Contains(buf.String(), "rlog: handler panicked: boom") {
+		t.Errorf("got stderr %q", buf.String())
+	}
+
+	SetRecoverHandlerPanics(false)
+	defer SetRecoverHandlerPanics(true)
+
+	defer func() {
+		if recover() != "boom" {
+			t.Error("got no panic with the recovery disabled")
+		}
+	}()
+
+	logger.Info("b")
+}
+
+// The handler counts the calls of 'Enabled()'.
+type countingHandler struct {
+	calls  int
+	static bool
+}
+
+func (h *countingHandler) Enabled(l LogLevel) bool {
+	h.calls++
+	return l >= LogLevelWarn
+}
+
+func (h *countingHandler) StaticLevel() (LogLevel, bool) {
+	return LogLevelWarn, h.static
+}
+
+func (h *countingHandler) Handle(LogRecord) {}
+
+func TestEnabledReflectsTheHandler(t *testing.T) {
+	logger, _ := newTestLogger(t, LogLevelWarn)
+
+	for l, want := range map[LogLevel]bool{
+		LogLevelDebug: false,
+		LogLevelInfo:  false,
+		LogLevelWarn:  true,
+		LogLevelError: true,
+	} {
+		if got := logger.Enabled(l); got != want {
+			t.Errorf("got %v for %s, want %v", got, l, want)
+		}
+	}
+
+	if (nopLogger{}).Enabled(LogLevelFatal) {
+		t.Error("got the nop logger enabled")
+	}
+}
+
+func TestStaticLevelIsCached(t *testing.T) {
+	for _, static := range []bool{true, false} {
+		h := &countingHandler{static: static}
+		logger := newLogger(h)
+
+		logger.Info("dropped")
+		logger.Warn("kept")
+
+		if want := map[bool]int{true: 0, false: 2}[static]; h.calls != want {
+			t.Errorf("got %d calls with static %v, want %d", h.calls, static, want)
+		}
+		if logger.Enabled(LogLevelInfo) || !logger.Enabled(LogLevelError) {
+			t.Errorf("got the wrong enablement with static %v", static)
+		}
+	}
+
+	// The '*LevelVar' is never cached.
+	v := NewLevelVar(LogLevelWarn)
+	logger := newLogger(NewJSONHandler(&bytes.Buffer{}, v))
+	v.Set(LogLevelDebug)
+	if !logger.Enabled(LogLevelDebug) {
+		t.Error("got the level var cached")
+	}
+}
+
+func TestLogAtLevel(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	logger.Log(LogLevelDebug, "dropped")
+	logger.Log(LogLevelError, "a", "k", 1)
+	logger.Log(LogLevel(7), "custom")
+
+	rs := snapshot()
+	if len(rs) != 2 || rs[0].Level != LogLevelError || rs[0].Message != "a" ||
+		len(rs[0].Attrs) != 1 || rs[1].Level != LogLevel(7) {
+		t.Errorf("got %v", rs)
+	}
+}
+
+func TestListLoggers(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	before := len(ListLoggers())
+	RegisterLogHandler(t.Name()+"-b", NewDiscardHandler())
+	RegisterLogHandler(t.Name()+"-a", NewDiscardHandler())
+
+	names := ListLoggers()
+	if len(names) != before+2 || !sort.StringsAreSorted(names) {
+		t.Errorf("got %v, want 2 more sorted names", names)
+	}
+}
+
+func TestRegisterLogHandlerE(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	if err := RegisterLogHandlerE(t.Name(), NewDiscardHandler()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := RegisterLogHandlerE(t.Name(), NewDiscardHandler())
+
+	var exists ErrHandlerExists
+	if !errors.As(err, &exists) || exists.Name != t.Name() ||
+		!strings.Contains(err.Error(), t.Name()) {
+		t.Errorf("got %v, want ErrHandlerExists of %q", err, t.Name())
+	}
+}
+
+func TestNilHandlerIsRejected(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	if RegisterLogHandler(t.Name(), nil) ||
+		RegisterLogHandlerWithAttrs(t.Name(), nil, Int("k", 1)) ||
+		ReplaceLogHandler(t.Name(), nil) || SetDefaultLogHandler(nil) {
+		t.Error("got nil handler registered")
+	}
+	if err := RegisterLogHandlerE(t.Name(), nil); err != ErrNilHandler {
+		t.Errorf("got %v, want ErrNilHandler", err)
+	}
+	if err := SwapHandler(t.Name(), nil); err != ErrNilHandler {
+		t.Errorf("got %v, want ErrNilHandler", err)
+	}
+	if _, ok := LookupLogger(t.Name()); ok {
+		t.Error("got registered")
+	}
+}
+
+func TestWithLevel(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	quiet := logger.WithLevel(LogLevelError)
+	quiet.Warn("dropped")
+	quiet.Error("a")
+
+	// The handler level still applies, and the last 'WithLevel()' wins.
+	loud := quiet.WithLevel(LogLevelDebug)
+	loud.Debug("dropped")
+	loud.Info("b")
+	logger.Warn("c")
+
+	rs := snapshot()
+	if len(rs) != 3 || rs[0].Message != "a" || rs[1].Message != "b" || rs[2].Message != "c" {
+		t.Errorf("got %v, want a, b and c", rs)
+	}
+	if quiet.Enabled(LogLevelWarn) || loud.Enabled(LogLevelDebug) {
+		t.Error("got the wrong enablement")
+	}
+}
+
+func TestScopedLevel(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	ScopedLevel(logger, LogLevelDebug, func(l ILogger) {
+		l.Debug("a")
+		l.With("k", 1).Debug("b")
+
+		// The other callers sharing the handler are not affected.
+		logger.Debug("dropped")
+	})
+	logger.Debug("dropped")
+
+	rs := snapshot()
+	if len(rs) != 2 || rs[0].Message != "a" || rs[1].Message != "b" {
+		t.Errorf("got %v, want a and b", rs)
+	}
+
+	ScopedLevel(nopLogger{}, LogLevelDebug, func(l ILogger) {
+		if l.Enabled(LogLevelDebug) {
+			t.Error("got the nop logger enabled")
+		}
+	})
+}
+
+func TestWithLevelIsInherited(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelDebug)
+
+	parent := logger.WithLevel(LogLevelWarn)
+	child := parent.With("k", "v")
+	grandchild := child.WithGroup("g").With("n", 1)
+
+	for _, l := range []ILogger{child, grandchild} {
+		l.Debug("dropped")
+		l.Info("dropped")
+		l.Warn("kept")
+
+		if l.Enabled(LogLevelDebug) {
+			t.Error("got debug enabled in the child")
+		}
+	}
+
+	rs := snapshot()
+	if len(rs) != 2 || rs[0].Message != "kept" || rs[1].Message != "kept" {
+		t.Fatalf("got %v, want 2 kept", rs)
+	}
+	if got := rs[0].ToMap(); got["k"] != "v" {
+		t.Errorf("got %v, want k=v", got)
+	}
+	if got := rs[1].ToMap(); got["k"] != "v" ||
+		!reflect.DeepEqual(got["g"], map[string]any{"n": 1}) {
+		t.Errorf("got %v, want k=v and g.n=1", got)
+	}
+}
+
+// The handler records the calls of 'Flush()' and 'Close()'.
+type lifecycleHandler struct {
+	discardHandler
+	calls []string
+	err   error
+}
+
+func (h *lifecycleHandler) Flush() error {
+	h.calls = append(h.calls, "flush")
+	return nil
+}
+
+func (h *lifecycleHandler) Close() error {
+	h.calls = append(h.calls, "close")
+	return h.err
+}
+
+func TestSnapshotRegistryRestores(t *testing.T) {
+	withPendingBufferSize(t, 0)
+
+	kept := NewDiscardHandler()
+	RegisterLogHandler(t.Name()+"-kept", kept)
+
+	restore := SnapshotRegistry()
+	RegisterLogHandler(t.Name()+"-added", NewDiscardHandler())
+	ReplaceLogHandler(t.Name()+"-kept", NewDiscardHandler())
+	SetFallbackHandler(NewDiscardHandler())
+	restore()
+
+	if _, ok := LookupLogger(t.Name() + "-added"); ok {
+		t.Error("got the added handler after the restore")
+	}
+	if v, ok := loggers.Load(t.Name() + "-kept"); !ok || v.(*registration).h != kept {
+		t.Error("got the replaced handler after the restore")
+	}
+	if _, ok := GetLogger(t.Name() + "-missing").(nopLogger); !ok {
+		t.Error("got the fallback handler after the restore")
+	}
+}
+
+func TestCloseAll(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	ok := &lifecycleHandler{}
+	failed := &lifecycleHandler{err: errors.New("disk gone")}
+	RegisterLogHandler(t.Name()+"-ok", ok)
+	RegisterLogHandler(t.Name()+"-failed", NewMultiHandler(NewDiscardHandler(), failed))
+
+	err := CloseAll()
+	if err == nil || !strings.Contains(err.Error(), "disk gone") {
+		t.Errorf("got %v, want the close error", err)
+	}
+
+	for _, h := range []*lifecycleHandler{ok, failed} {
+		if strings.Join(h.calls, " ") != "flush close" {
+			t.Errorf("got calls %v, want flush then close", h.calls)
+		}
+	}
+}
+
+func TestCloseAllClosesSharedHandlerOnce(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	shared := &lifecycleHandler{}
+	RegisterLogHandler(t.Name()+"-a", shared)
+	RegisterLogHandler(t.Name()+"-b", shared)
+
+	// The uncomparable handlers never panic as the map keys.
+	closes := 0
+	RegisterLogHandler(t.Name()+"-c", uncomparableHandler{closes: &closes})
+
+	if err := CloseAll(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(shared.calls, " ") != "flush close" {
+		t.Errorf("got calls %v, want flush then close once", shared.calls)
+	}
+	if closes != 1 {
+		t.Errorf("got %d closes of the uncomparable handler, want 1", closes)
+	}
+}
+
+// The handler can't be a map key, since it has a slice.
+type uncomparableHandler struct {
+	discardHandler
+	closes *int
+	_      []int
+}
+
+func (h uncomparableHandler) Close() error {
+	*h.closes++
+	return nil
+}
+
+func TestFallbackHandler(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	fallback, snapshot := NewMemoryHandler(LogLevelInfo)
+	SetFallbackHandler(fallback)
+
+	logger, ok := LookupLogger(t.Name())
+	if ok {
+		t.Error("got registered")
+	}
+	logger.Info("a")
+	GetLoggerWithModule(t.Name(), "db").Info("b")
+
+	rs := snapshot()
+	if len(rs) != 2 || rs[1].Attrs[0] != (LogAttr{Key: KEY_MODULE, Value: "db"}) {
+		t.Errorf("got %v, want a and b with the module", rs)
+	}
+
+	// Removed by nil, and the old one is restored by the snapshot.
+	SetFallbackHandler(nil)
+	GetLogger(t.Name()).Info("dropped")
+	if n := len(snapshot()); n != 2 {
+		t.Errorf("got %d records after the removal, want 2", n)
+	}
+}
+
+func TestNameIsKeptByDerivedLoggers(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+	RegisterLogHandler(t.Name(), NewDiscardHandler())
+
+	if name := GetLogger(t.Name()).Name(); name != "" {
+		t.Errorf("got name %q, want empty", name)
+	}
+
+	logger := GetLoggerWithModule(t.Name(), "db")
+	for _, l := range []ILogger{
+		logger, logger.With("k", 1), logger.WithGroup("g"), logger.WithLevel(LogLevelWarn),
+	} {
+		if l.Name() != "db" {
+			t.Errorf("got name %q, want db", l.Name())
+		}
+	}
+}
+
+func TestMapArgsAreSortedByKey(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	logger.With(map[string]any{"b": 2, "a": 1}).Info("m", "c", 3, map[string]any{"e": 5, "d": 4})
+
+	keys := []string{}
+	for _, a := range snapshot()[0].Attrs {
+		keys = append(keys, a.Key)
+	}
+	if got := strings.Join(keys, " "); got != "a b c d e" {
+		t.Errorf("got keys %q, want a b c d e", got)
+	}
+}
+
+func TestRegisteredAttrsAreScopedToTheName(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+	attrs := []LogAttr{Str("app", "a")}
+	RegisterLogHandlerWithAttrs(t.Name()+"-a", h, attrs...)
+	RegisterLogHandler(t.Name()+"-b", h)
+
+	// The attributes are copied.
+	attrs[0].Value = "changed"
+
+	GetLogger(t.Name()+"-a").Info("a", "k", 1)
+	GetLogger(t.Name() + "-b").Info("b")
+
+	rs := snapshot()
+	if len(rs) != 2 || len(rs[0].Attrs) != 2 ||
+		rs[0].Attrs[0] != (LogAttr{Key: "app", Value: "a"}) || len(rs[1].Attrs) != 0 {
+		t.Errorf("got %v, want app of a only", rs)
+	}
+}
+
+func TestPrintfVariants(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	logger.Debugf("dropped %d", 0)
+	logger.Infof("user %s logged in", "bob")
+	logger.Warnf("%d retries", 3)
+	logger.Errorf("failed: %v", errors.New("boom"))
+
+	rs := snapshot()
+	if len(rs) != 3 {
+		t.Fatalf("got %d records, want 3", len(rs))
+	}
+
+	want := []string{"user bob logged in", "3 retries", "failed: boom"}
+	for i, r := range rs {
+		if r.Message != want[i] || len(r.Attrs) != 0 {
+			t.Errorf("got %q %v, want %q", r.Message, r.Attrs, want[i])
+		}
+	}
+}
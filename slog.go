@@ -0,0 +1,174 @@
+//go:build go1.21
+
+package rlog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// The handler forwards the records to a 'slog.Handler', so apps could reuse
+// their 'slog' setup through the rlog interface.
+type slogHandler struct {
+	h slog.Handler
+}
+
+func NewSlogHandler(h slog.Handler) LogHandler {
+	return &slogHandler{h: h}
+}
+
+func (h *slogHandler) Enabled(l LogLevel) bool {
+	return h.h.Enabled(context.Background(), toSlogLevel(l))
+}
+
+func (h *slogHandler) Handle(r LogRecord) {
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	sr := slog.NewRecord(r.Time, toSlogLevel(r.Level), r.Message, 0)
+
+	for _, attr := range r.Attrs {
+		sr.AddAttrs(toSlogAttr(attr))
+	}
+
+	_ = h.h.Handle(ctx, sr)
+}
+
+func toSlogAttr(attr LogAttr) slog.Attr {
+	attr = attr.Resolve()
+
+	group, ok := attr.Value.([]LogAttr)
+	if !ok {
+		return slog.Any(attr.Key, attr.Value)
+	}
+
+	as := make([]slog.Attr, len(group))
+	for i, a := range group {
+		as[i] = toSlogAttr(a)
+	}
+
+	return slog.Attr{Key: attr.Key, Value: slog.GroupValue(as...)}
+}
+
+// The levels in 'slog' are spaced by 4, ex: 'slog.LevelInfo' is 0 and
+// 'slog.LevelWarn' is 4.
+func toSlogLevel(l LogLevel) slog.Level {
+	return slog.Level(int(l) * 4)
+}
+
+// There is no fatal level in 'slog', use the next one of 'slog.LevelError'.
+const slogLevelFatal = slog.LevelError + 4
+
+func fromSlogLevel(l slog.Level) LogLevel {
+	switch {
+	case l >= slogLevelFatal:
+		return LogLevelFatal
+	case l >= slog.LevelError:
+		return LogLevelError
+	case l >= slog.LevelWarn:
+		return LogLevelWarn
+	case l >= slog.LevelInfo:
+		return LogLevelInfo
+	default:
+		return LogLevelDebug
+	}
+}
+
+// The 'slog.Handler' forwards the records to a rlog handler, so the code written
+// against 'slog' could share the rlog implementation.
+//
+// The attributes and groups are bound to a rlog logger, same as 'With()' and
+// 'WithGroup()' of the 'ILogger'.
+type asSlogHandler struct {
+	l *r_logger
+}
+
+func AsSlogHandler(h LogHandler) slog.Handler {
+	return &asSlogHandler{l: newLogger(h)}
+}
+
+func (h *asSlogHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.l.enabled(fromSlogLevel(l))
+}
+
+func (h *asSlogHandler) Handle(ctx context.Context, sr slog.Record) error {
+	attrs := make([]LogAttr, 0, sr.NumAttrs())
+
+	sr.Attrs(func(a slog.Attr) bool {
+		attrs = appendSlogAttr(attrs, a)
+		return true
+	})
+
+	r := LogRecord{
+		Time:    sr.Time,
+		Message: sr.Message,
+		Attrs:   h.l.collectAttrs(attrs),
+		Level:   fromSlogLevel(sr.Level),
+		Context: ctx,
+	}
+
+	if sr.PC != 0 && captureSource(h.l.handler) {
+		r.Source = sourceOf(sr.PC)
+	}
+
+	h.l.handler.Handle(r)
+
+	return nil
+}
+
+func (h *asSlogHandler) WithAttrs(as []slog.Attr) slog.Handler {
+	if len(as) == 0 {
+		return h
+	}
+
+	args := make([]any, 0, len(as))
+	for _, attr := range appendSlogAttrs(nil, as) {
+		args = append(args, attr)
+	}
+
+	return &asSlogHandler{l: h.l.With(args...).(*r_logger)}
+}
+
+func (h *asSlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &asSlogHandler{l: h.l.WithGroup(name).(*r_logger)}
+}
+
+func appendSlogAttrs(attrs []LogAttr, as []slog.Attr) []LogAttr {
+	for _, a := range as {
+		attrs = appendSlogAttr(attrs, a)
+	}
+
+	return attrs
+}
+
+// Convert the 'slog.Attr', the group is converted to a rlog group, except the
+// one with empty key is inlined, and the empty attributes are ignored, same as
+// the rules of 'slog'.
+func appendSlogAttr(attrs []LogAttr, a slog.Attr) []LogAttr {
+	v := a.Value.Resolve()
+
+	if v.Kind() != slog.KindGroup {
+		if a.Key == "" {
+			return attrs
+		}
+
+		return append(attrs, LogAttr{Key: a.Key, Value: v.Any()})
+	}
+
+	if a.Key == "" {
+		return appendSlogAttrs(attrs, v.Group())
+	}
+
+	group := appendSlogAttrs(nil, v.Group())
+	if len(group) == 0 {
+		return attrs
+	}
+
+	return append(attrs, LogAttr{Key: a.Key, Value: group})
+}
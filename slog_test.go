@@ -0,0 +1,69 @@
+//go:build go1.21
+
+package rlog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	buf := bytes.Buffer{}
+	h := NewSlogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	RegisterLogHandler(t.Name(), h)
+
+	logger := GetLogger(t.Name())
+	logger.Info("dropped")
+	logger.Error("failed", "k", 1, Group("g", "n", "v"))
+
+	if want := "level=ERROR msg=failed k=1 g.n=v\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSlogLevels(t *testing.T) {
+	for _, l := range []LogLevel{
+		LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelFatal,
+	} {
+		if got := fromSlogLevel(toSlogLevel(l)); got != l {
+			t.Errorf("got %v, want %v", got, l)
+		}
+	}
+}
+
+func TestAsSlogHandler(t *testing.T) {
+	h, snapshot := NewMemoryHandler(LogLevelInfo)
+	logger := slog.New(AsSlogHandler(h))
+
+	logger.Debug("dropped")
+	logger.With("a", 1).WithGroup("g").Info("hi", "k", "v",
+		slog.Group("", "inlined", true), slog.Attr{})
+
+	rs := snapshot()
+	if len(rs) != 1 || rs[0].Message != "hi" || rs[0].Level != LogLevelInfo {
+		t.Fatalf("got %v, want hi", rs)
+	}
+
+	attrs := rs[0].Attrs
+	if len(attrs) != 2 || attrs[0] != (LogAttr{Key: "a", Value: int64(1)}) {
+		t.Fatalf("got attrs %v", attrs)
+	}
+
+	group, ok := attrs[1].Value.([]LogAttr)
+	if attrs[1].Key != "g" || !ok || len(group) != 2 ||
+		group[0] != (LogAttr{Key: "k", Value: "v"}) ||
+		group[1] != (LogAttr{Key: "inlined", Value: true}) {
+		t.Errorf("got group %v", attrs[1])
+	}
+}
@@ -0,0 +1,77 @@
+package rlog
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// The writer emits each written line as the message of a record.
+type writerAdapter struct {
+	l     ILogger
+	level LogLevel
+
+	mu  sync.Mutex // Guards 'buf'.
+	buf []byte     // The partial line not ended with a newline.
+}
+
+// The function name of 'writerAdapter.Write()', ex:
+// "github.com/leoadonia/rlog.(*writerAdapter).Write".
+var writerAdapterWrite = runtime.FuncForPC(
+	reflect.ValueOf((*writerAdapter).Write).Pointer()).Name()
+
+// Whether the frame is of 'writerAdapter.Write()' or the standard logger, which
+// are skipped in the source locations.
+func isBridgeFrame(function string) bool {
+	return function == writerAdapterWrite || strings.HasPrefix(function, "log.")
+}
+
+// Return a writer which emits each line written to it as a record at 'level'
+// with 'l', ex: for the libraries which only accept an 'io.Writer'. The line
+// not ended with a newline is buffered until the rest is written, and the
+// empty lines are skipped.
+//
+// The source location of the records is the caller of 'Write()', or of the
+// standard logger if it writes to this, ex: the 'log.Printf()' call site.
+func NewWriterAdapter(l ILogger, level LogLevel) io.Writer {
+	return &writerAdapter{l: l, level: level}
+}
+
+func (w *writerAdapter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	start := 0
+
+	for {
+		i := bytes.IndexByte(w.buf[start:], '\n')
+		if i < 0 {
+			break
+		}
+
+		line := bytes.TrimSuffix(w.buf[start:start+i], []byte{'\r'})
+		if len(line) > 0 {
+			w.l.Log(w.level, string(line))
+		}
+
+		start += i + 1
+	}
+
+	// Move the partial line to the front, so the buffer is reused.
+	n := copy(w.buf, w.buf[start:])
+	w.buf = w.buf[:n]
+
+	return len(p), nil
+}
+
+// Return a standard logger whose output is emitted as the records at 'level'
+// with 'l', ex: to bridge the legacy 'log.Printf()' calls. The date and time
+// prefix is disabled, since the records carry their own time.
+func NewStdLogger(l ILogger, level LogLevel) *log.Logger {
+	return log.New(NewWriterAdapter(l, level), "", 0)
+}
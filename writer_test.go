@@ -0,0 +1,80 @@
+package rlog
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// The handler keeps the source locations of the records.
+type sourceRecorder struct {
+	mu      sync.Mutex
+	sources []*LogSource
+}
+
+func (h *sourceRecorder) Enabled(LogLevel) bool { return true }
+
+func (h *sourceRecorder) CaptureSource() bool { return true }
+
+func (h *sourceRecorder) Handle(r LogRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sources = append(h.sources, r.Source)
+}
+
+func TestWriterAdapterReportsCallerSource(t *testing.T) {
+	t.Cleanup(SnapshotRegistry())
+
+	h := &sourceRecorder{}
+	RegisterLogHandler(t.Name(), h)
+	l := GetLogger(t.Name())
+
+	NewWriterAdapter(l, LogLevelInfo).Write([]byte("direct\n"))
+	NewStdLogger(l, LogLevelWarn).Printf("std %d", 1)
+
+	if len(h.sources) != 2 {
+		t.Fatalf("got %d records, want 2", len(h.sources))
+	}
+
+	for _, s := range h.sources {
+		if s == nil || filepath.Base(s.File) != "writer_test.go" {
+			t.Errorf("got source %+v, want in writer_test.go", s)
+		}
+	}
+}
+
+func TestWriterAdapterSplitsLines(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+	w := NewWriterAdapter(logger, LogLevelWarn)
+
+	for _, s := range []string{"a\nb", "c\r\n\n", "d\ne\n", "partial"} {
+		if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+			t.Errorf("got %d %v, want %d", n, err, len(s))
+		}
+	}
+
+	msgs := []string{}
+	for _, r := range snapshot() {
+		if r.Level != LogLevelWarn {
+			t.Errorf("got level %s, want WARN", r.Level)
+		}
+		msgs = append(msgs, r.Message)
+	}
+
+	if got := strings.Join(msgs, "|"); got != "a|bc|d|e" {
+		t.Errorf("got %q, want a|bc|d|e", got)
+	}
+}
+
+func TestStdLoggerEmitsRecords(t *testing.T) {
+	logger, snapshot := newTestLogger(t, LogLevelInfo)
+
+	NewStdLogger(logger, LogLevelError).Printf("failed %d", 1)
+
+	if rs := snapshot(); len(rs) != 1 || rs[0].Message != "failed 1" ||
+		rs[0].Level != LogLevelError {
+		t.Errorf("got %v, want one error record", rs)
+	}
+}